@@ -0,0 +1,173 @@
+// Copyright 2021 Aadhav Vignesh
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octopool
+
+import (
+	"sync"
+	"time"
+)
+
+// pre-defined autoscaling interval
+const defaultAutoscaleInterval = time.Second
+
+// pre-defined keep-alive duration for idle workers
+const defaultKeepAlive = time.Minute
+
+// AutoscaleConfig is a struct for representing how the pool capacity is scaled between a minimum and a maximum.
+//
+// At every interval, the capacity grows to absorb the job queue, and shrinks to the workers which
+// were busy within the keep-alive duration. Idle workers parked for longer than the keep-alive
+// duration are stopped, keeping at least the minimum number of workers.
+type AutoscaleConfig struct {
+	MinWorkers int                    // lower bound for the capacity, defaults to 1
+	MaxWorkers int                    // upper bound for the capacity, defaults to the pool capacity
+	Interval   time.Duration          // time between two scaling decisions, defaults to one second
+	KeepAlive  time.Duration          // time after which idle workers are stopped, defaults to one minute
+	OnScale    func(event ScaleEvent) // called for every scaling decision which changed the pool, may be nil
+}
+
+// ScaleEvent is a struct for representing a scaling decision of the autoscaler.
+type ScaleEvent struct {
+	From          int       // capacity before the decision
+	To            int       // capacity after the decision
+	Reaped        int       // number of idle workers stopped
+	QueuedJobs    int       // number of jobs in the job queue when the decision was made
+	ActiveWorkers int       // number of active workers when the decision was made
+	At            time.Time // time at which the decision was made
+}
+
+// autoscaler is a struct for representing the state of the autoscaler.
+type autoscaler struct {
+	config AutoscaleConfig // scaling configuration
+	timer  Timer           // fires at the next scaling decision
+	mu     sync.Mutex      // mutex for locking the timer
+}
+
+// Starts making scaling decisions, the pool capacity is moved within the bounds first.
+func (octo *Octopus) startAutoscaler() {
+	config := &octo.autoscaler.config
+	if config.MinWorkers <= 0 {
+		config.MinWorkers = 1
+	}
+	if config.MaxWorkers <= 0 {
+		config.MaxWorkers = octo.workerPool.getPoolCapacity()
+	}
+	if config.MaxWorkers < config.MinWorkers {
+		octo.logger.Warn("invalid autoscaling bounds, using the minimum as the maximum",
+			"min_workers", config.MinWorkers, "max_workers", config.MaxWorkers)
+		config.MaxWorkers = config.MinWorkers
+	}
+	if config.Interval <= 0 {
+		config.Interval = defaultAutoscaleInterval
+	}
+	if config.KeepAlive <= 0 {
+		config.KeepAlive = defaultKeepAlive
+	}
+
+	capacity := octo.workerPool.getPoolCapacity()
+	octo.workerPool.resize(clamp(capacity, config.MinWorkers, config.MaxWorkers))
+
+	octo.autoscaler.mu.Lock()
+	octo.autoscaler.timer = octo.clock.AfterFunc(config.Interval, octo.autoscale)
+	octo.autoscaler.mu.Unlock()
+}
+
+// Stops making scaling decisions.
+func (octo *Octopus) stopAutoscaler() {
+	if octo.autoscaler == nil {
+		return
+	}
+
+	octo.autoscaler.mu.Lock()
+	defer octo.autoscaler.mu.Unlock()
+
+	if octo.autoscaler.timer != nil {
+		octo.autoscaler.timer.Stop()
+	}
+}
+
+// Makes a scaling decision, and sets the timer for the next one.
+func (octo *Octopus) autoscale() {
+	if octo.workerPool.isClosed() {
+		return
+	}
+
+	config := octo.autoscaler.config
+	now := octo.clock.Now()
+	queued := octo.jobQueue.Len()
+
+	event := octo.workerPool.autoscale(config, queued, now.Add(-config.KeepAlive))
+	event.At = now
+
+	if event.To > event.From {
+		// assign queued jobs to the new workers
+		octo.processNext()
+	}
+
+	if event.From != event.To || event.Reaped > 0 {
+		octo.logger.Info("autoscaled pool", "from", event.From, "to", event.To, "reaped", event.Reaped,
+			"queued", event.QueuedJobs, "active", event.ActiveWorkers)
+		if config.OnScale != nil {
+			config.OnScale(event)
+		}
+	}
+
+	octo.autoscaler.mu.Lock()
+	defer octo.autoscaler.mu.Unlock()
+
+	if !octo.workerPool.isClosed() {
+		octo.autoscaler.timer = octo.clock.AfterFunc(config.Interval, octo.autoscale)
+	}
+}
+
+// Stops the idle workers parked before the expiry, and moves the capacity within the bounds of the configuration.
+// The capacity grows to absorb the queued jobs, or shrinks to the busy and remaining idle workers.
+func (p *pool) autoscale(config AutoscaleConfig, queued int, expiry time.Time) ScaleEvent {
+	p.mu.Lock()
+
+	event := ScaleEvent{From: p.capacity, QueuedJobs: queued, ActiveWorkers: p.activeWorkers}
+
+	// stop the least recently parked idle workers first, keeping the minimum number of workers
+	expired := 0
+	for expired < len(p.idleWorkers) && !p.idleWorkers[expired].parkedAt.After(expiry) &&
+		p.spawnedWorkers-expired > config.MinWorkers {
+		expired++
+	}
+	reaped := make([]*worker, expired)
+	copy(reaped, p.idleWorkers)
+	p.idleWorkers = append(p.idleWorkers[:0], p.idleWorkers[expired:]...)
+	p.spawnedWorkers -= expired
+	event.Reaped = expired
+
+	capacity := p.activeWorkers + len(p.idleWorkers)
+	if queued > 0 {
+		// never shrink while jobs are waiting
+		capacity = max(p.activeWorkers+queued, p.capacity)
+	}
+	p.capacity = clamp(capacity, config.MinWorkers, config.MaxWorkers)
+	event.To = p.capacity
+
+	p.mu.Unlock()
+
+	for _, w := range reaped {
+		close(w.jobs)
+	}
+	return event
+}
+
+// Returns the value moved within the bounds.
+func clamp(value, lower, upper int) int {
+	return min(max(value, lower), upper)
+}
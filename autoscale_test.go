@@ -0,0 +1,115 @@
+// Copyright 2021 Aadhav Vignesh
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octopool
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Returns an autoscaled octopus using a fake clock, the clock, and the scaling events.
+func newAutoscaledOctopus(t *testing.T, config AutoscaleConfig) (*Octopus, *FakeClock, *[]ScaleEvent) {
+	t.Helper()
+
+	var events []ScaleEvent
+	config.OnScale = func(event ScaleEvent) {
+		events = append(events, event)
+	}
+
+	clock := NewFakeClock(fakeStart)
+	testOctopus := NewOctopusWithOptions(config.MaxWorkers, queueCapacity, WithClock(clock), WithAutoscaling(config))
+	t.Cleanup(testOctopus.Close)
+
+	return testOctopus, clock, &events
+}
+
+// Test for checking that the capacity grows with the job queue, and shrinks once idle workers are reaped.
+func TestAutoscale(t *testing.T) {
+	testOctopus, clock, events := newAutoscaledOctopus(t, AutoscaleConfig{
+		MinWorkers: 1,
+		MaxWorkers: 8,
+		Interval:   time.Second,
+		KeepAlive:  10 * time.Second,
+	})
+
+	// no workers are busy, the capacity shrinks to the minimum
+	clock.Advance(time.Second)
+	assert.Equal(t, 1, testOctopus.PoolCapacity())
+	assert.Equal(t, []ScaleEvent{{From: 8, To: 1, At: fakeStart.Add(time.Second)}}, *events)
+
+	release := make(chan struct{})
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, testOctopus.HandleJob(func() { <-release }))
+	}
+	assert.Equal(t, 1, testOctopus.ActiveWorkers())
+
+	// the capacity grows to absorb the job queue
+	clock.Advance(time.Second)
+	assert.Equal(t, 5, testOctopus.PoolCapacity())
+	assert.Equal(t, 5, testOctopus.ActiveWorkers())
+	assert.Equal(t, ScaleEvent{From: 1, To: 5, QueuedJobs: 4, ActiveWorkers: 1, At: fakeStart.Add(2 * time.Second)}, (*events)[1])
+
+	close(release)
+	testOctopus.Wait()
+
+	// idle workers are kept during the keep-alive duration
+	clock.Advance(9 * time.Second)
+	assert.Equal(t, 5, testOctopus.PoolCapacity())
+	assert.Len(t, *events, 2)
+
+	// idle workers are reaped once the keep-alive duration has passed, keeping the minimum
+	clock.Advance(time.Second)
+	assert.Equal(t, 1, testOctopus.PoolCapacity())
+	assert.Equal(t, 1, testOctopus.workerPool.spawnedWorkers)
+	assert.Equal(t, ScaleEvent{From: 5, To: 1, Reaped: 4, At: fakeStart.Add(12 * time.Second)}, (*events)[2])
+}
+
+// Test for checking that the capacity stays within the bounds.
+func TestAutoscaleBounds(t *testing.T) {
+	testOctopus, clock, _ := newAutoscaledOctopus(t, AutoscaleConfig{
+		MinWorkers: 2,
+		MaxWorkers: 3,
+		Interval:   time.Second,
+	})
+
+	release := make(chan struct{})
+	for i := 0; i < 10; i++ {
+		assert.NoError(t, testOctopus.HandleJob(func() { <-release }))
+	}
+
+	clock.Advance(time.Second)
+	assert.Equal(t, 3, testOctopus.PoolCapacity())
+	assert.Equal(t, 3, testOctopus.ActiveWorkers())
+
+	close(release)
+	testOctopus.Wait()
+
+	clock.Advance(time.Hour)
+	assert.Equal(t, 2, testOctopus.PoolCapacity())
+	assert.Equal(t, 2, testOctopus.workerPool.spawnedWorkers)
+}
+
+// Test for checking that the autoscaler stops once the octopus is closed.
+func TestAutoscaleClose(t *testing.T) {
+	testOctopus, clock, events := newAutoscaledOctopus(t, AutoscaleConfig{MaxWorkers: 4})
+	testOctopus.Close()
+
+	clock.Advance(time.Hour)
+
+	assert.Empty(t, *events)
+	assert.Equal(t, 4, testOctopus.PoolCapacity())
+}
@@ -2,6 +2,8 @@ package octopool_test
 
 import (
 	"fmt"
+	"runtime/metrics"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -9,7 +11,7 @@ import (
 )
 
 func benchmarkOctopus(poolCapacity int, queueCapacity int, b *testing.B) {
-	pool := octopool.NewOctopus(poolCapacity, queueCapacity)
+	pool := octopool.NewOctopusWithOptions(poolCapacity, queueCapacity, octopool.WithOverflowPolicy(octopool.OverflowBlock))
 
 	job1 := func() {
 		time.Sleep(100 * time.Millisecond)
@@ -39,3 +41,49 @@ func Benchmark_Octopus_Pool1000_Queue10000(b *testing.B) {
 func Benchmark_Octopus_Pool10000_Queue100000(b *testing.B) {
 	benchmarkOctopus(10000, 100000, b)
 }
+
+// Returns the number of goroutines created so far, if the runtime reports it.
+func goroutinesCreated() (uint64, bool) {
+	sample := []metrics.Sample{{Name: "/sched/goroutines-created:goroutines"}}
+	metrics.Read(sample)
+
+	if sample[0].Value.Kind() != metrics.KindUint64 {
+		return 0, false
+	}
+	return sample[0].Value.Uint64(), true
+}
+
+// Benchmarks jobs which finish immediately, so that the cost of handing jobs to workers dominates.
+func benchmarkOctopusShortJobs(poolCapacity int, b *testing.B) {
+	pool := octopool.NewOctopusWithOptions(poolCapacity, 1024, octopool.WithOverflowPolicy(octopool.OverflowBlock))
+
+	var sum int64
+	job1 := func() {
+		atomic.AddInt64(&sum, 1)
+	}
+
+	b.ReportAllocs()
+	created, ok := goroutinesCreated()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		err := pool.HandleJob(job1, "short-octojob")
+		if err != nil {
+			fmt.Println(err.Error())
+		}
+	}
+	pool.Wait()
+
+	b.StopTimer()
+	if now, ok2 := goroutinesCreated(); ok && ok2 {
+		b.ReportMetric(float64(now-created)/float64(b.N), "goroutines/op")
+	}
+}
+
+func Benchmark_Octopus_ShortJobs_Pool10(b *testing.B) {
+	benchmarkOctopusShortJobs(10, b)
+}
+
+func Benchmark_Octopus_ShortJobs_Pool100(b *testing.B) {
+	benchmarkOctopusShortJobs(100, b)
+}
@@ -0,0 +1,142 @@
+// Copyright 2021 Aadhav Vignesh
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octopool
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock is an interface for representing the source of time used to schedule jobs.
+// It can be replaced using WithClock, e.g. with a FakeClock in tests.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// AfterFunc calls the function in its own goroutine once the duration has passed.
+	AfterFunc(d time.Duration, fun func()) Timer
+}
+
+// Timer is an interface for representing a function call scheduled by a Clock.
+type Timer interface {
+	// Stop prevents the call, returns false if the call already happened or was stopped.
+	Stop() bool
+}
+
+// realClock is a struct for representing the system's clock.
+type realClock struct{}
+
+// Now returns the current time.
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+// AfterFunc calls the function in its own goroutine once the duration has passed.
+func (realClock) AfterFunc(d time.Duration, fun func()) Timer {
+	return time.AfterFunc(d, fun)
+}
+
+// FakeClock is a struct for representing a clock which only moves when advanced, for deterministic tests.
+// It is safe for concurrent use.
+type FakeClock struct {
+	now    time.Time    // current time
+	timers []*fakeTimer // timers which have not fired or been stopped
+	mu     sync.Mutex   // mutex for locking
+}
+
+// fakeTimer is a struct for representing a function call scheduled by a FakeClock.
+type fakeTimer struct {
+	clock *FakeClock // clock which fires the timer
+	at    time.Time  // time at which the timer fires
+	fun   func()     // function called when the timer fires
+}
+
+// NewFakeClock returns a fake clock set to the time provided.
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+// Now returns the fake clock's current time.
+func (clock *FakeClock) Now() time.Time {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+
+	return clock.now
+}
+
+// AfterFunc calls the function once the clock has been advanced by the duration.
+// Timers only fire during Advance, including timers created with a duration equal to or less than zero.
+func (clock *FakeClock) AfterFunc(d time.Duration, fun func()) Timer {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+
+	timer := &fakeTimer{clock: clock, at: clock.now.Add(d), fun: fun}
+	clock.timers = append(clock.timers, timer)
+	return timer
+}
+
+// Advance moves the clock forward, and fires the timers which become due in the order they are due.
+// The timers' functions are called synchronously, and the clock reads the time each timer is due while it fires.
+func (clock *FakeClock) Advance(d time.Duration) {
+	clock.mu.Lock()
+	target := clock.now.Add(d)
+	clock.mu.Unlock()
+
+	for {
+		clock.mu.Lock()
+		timer := clock.nextTimer(target)
+		if timer == nil {
+			clock.now = target
+			clock.mu.Unlock()
+			return
+		}
+		if timer.at.After(clock.now) {
+			clock.now = timer.at
+		}
+		clock.mu.Unlock()
+
+		timer.fun()
+	}
+}
+
+// Removes and returns the earliest timer which is due at the target time, the clock's lock must be held.
+func (clock *FakeClock) nextTimer(target time.Time) *fakeTimer {
+	sort.SliceStable(clock.timers, func(i, j int) bool {
+		return clock.timers[i].at.Before(clock.timers[j].at)
+	})
+
+	if len(clock.timers) == 0 || clock.timers[0].at.After(target) {
+		return nil
+	}
+
+	timer := clock.timers[0]
+	clock.timers[0] = nil
+	clock.timers = clock.timers[1:]
+	return timer
+}
+
+// Stop prevents the call, returns false if the call already happened or was stopped.
+func (timer *fakeTimer) Stop() bool {
+	timer.clock.mu.Lock()
+	defer timer.clock.mu.Unlock()
+
+	for i, pending := range timer.clock.timers {
+		if pending == timer {
+			timer.clock.timers = append(timer.clock.timers[:i], timer.clock.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
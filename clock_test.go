@@ -0,0 +1,68 @@
+// Copyright 2021 Aadhav Vignesh
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octopool
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// pre-defined start time for fake clocks
+var fakeStart = time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)
+
+// Test for checking that a fake clock fires its timers in order, at the time they are due.
+func TestFakeClock(t *testing.T) {
+	clock := NewFakeClock(fakeStart)
+
+	var fired []time.Duration
+	record := func() { fired = append(fired, clock.Now().Sub(fakeStart)) }
+
+	clock.AfterFunc(2*time.Second, record)
+	clock.AfterFunc(time.Second, func() {
+		record()
+		// timers created while firing fire during the same advance if they are due
+		clock.AfterFunc(500*time.Millisecond, record)
+	})
+	stopped := clock.AfterFunc(time.Second, record)
+	clock.AfterFunc(time.Hour, record)
+
+	assert.True(t, stopped.Stop())
+	assert.False(t, stopped.Stop())
+
+	clock.Advance(3 * time.Second)
+
+	assert.Equal(t, []time.Duration{time.Second, 1500 * time.Millisecond, 2 * time.Second}, fired)
+	assert.Equal(t, fakeStart.Add(3*time.Second), clock.Now())
+}
+
+// Test for checking that scheduled jobs use the octopus' clock.
+func TestFakeClockScheduledJobs(t *testing.T) {
+	clock := NewFakeClock(fakeStart)
+	testOctopus := NewOctopusWithOptions(1, queueCapacity, WithClock(clock))
+
+	ran := false
+	scheduled, err := testOctopus.HandleJobAfter(time.Minute, func() { ran = true }, "delayed")
+	assert.NoError(t, err)
+	assert.Equal(t, fakeStart.Add(time.Minute), scheduled.At())
+
+	clock.Advance(59 * time.Second)
+	assert.Equal(t, 1, testOctopus.ScheduledJobs())
+
+	clock.Advance(time.Second)
+	testOctopus.Wait()
+	assert.True(t, ran)
+}
@@ -0,0 +1,383 @@
+// Copyright 2021 Aadhav Vignesh
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octopool
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrScheduleExists is the error raised when a schedule with the same name already exists.
+var ErrScheduleExists = errors.New("schedule already exists")
+
+// ErrScheduleNotFound is the error raised when no schedule exists with the name provided.
+var ErrScheduleNotFound = errors.New("schedule not found")
+
+// OverlapPolicy represents what a schedule does when it is due while its previous run is still active.
+type OverlapPolicy int
+
+const (
+	// OverlapSkip skips the run
+	OverlapSkip OverlapPolicy = 0
+	// OverlapQueue starts the run once the previous run has finished
+	OverlapQueue OverlapPolicy = 1
+	// OverlapConcurrent starts the run alongside the previous run
+	OverlapConcurrent OverlapPolicy = 2
+)
+
+// ScheduleOption is a function for configuring a schedule.
+type ScheduleOption func(entry *cronEntry)
+
+// WithLocation sets the time zone cron expressions are evaluated in, the default is the local time zone.
+// A time zone set in the expression using CRON_TZ takes precedence.
+func WithLocation(location *time.Location) ScheduleOption {
+	return func(entry *cronEntry) {
+		entry.location = location
+	}
+}
+
+// WithJitter delays every run by a random duration between zero and the jitter provided.
+func WithJitter(jitter time.Duration) ScheduleOption {
+	return func(entry *cronEntry) {
+		entry.jitter = jitter
+	}
+}
+
+// WithOverlapPolicy sets what happens when a run is due while the previous run is still active, the default is OverlapSkip.
+func WithOverlapPolicy(policy OverlapPolicy) ScheduleOption {
+	return func(entry *cronEntry) {
+		entry.overlapPolicy = policy
+	}
+}
+
+// ScheduleInfo is a struct for representing the state of a schedule.
+type ScheduleInfo struct {
+	Name    string    // name of the schedule, used as the name of its jobs
+	Spec    string    // cron expression or interval of the schedule
+	Next    time.Time // time of the next run without jitter, zero if paused or if there is none
+	LastRun time.Time // time at which the last run started
+	LastErr error     // error of the last run which finished
+	Paused  bool      // reports whether the schedule is paused
+	Running int       // number of runs which have not finished yet
+	Queued  int       // number of runs waiting for the previous run to finish
+	Runs    int       // number of runs started
+	Skipped int       // number of runs skipped as the previous run was still active
+}
+
+// cronEntry is a struct for representing a schedule registered with a Cron.
+type cronEntry struct {
+	ScheduleInfo
+	schedule      schedule       // times at which the job runs
+	fun           func()         // the job's function
+	location      *time.Location // time zone cron expressions are evaluated in
+	jitter        time.Duration  // maximum random delay of every run
+	overlapPolicy OverlapPolicy  // policy used when the previous run is still active
+	timer         Timer          // fires once the next run is due
+	generation    uint64         // identifies the current timer, so that stale timers are ignored
+	removed       bool           // reports whether the schedule was removed
+}
+
+// Cron is a struct for representing a scheduler which handles recurring jobs using an octopus.
+// Schedules use the octopus' clock, and every run is handled like a job named after its schedule.
+// It is safe for concurrent use.
+type Cron struct {
+	octopus *Octopus              // octopus which runs the jobs
+	entries map[string]*cronEntry // schedules by name
+	mu      sync.Mutex            // mutex for locking
+}
+
+// NewCron returns a scheduler which handles recurring jobs using the octopus provided.
+func NewCron(octo *Octopus) *Cron {
+	return &Cron{
+		octopus: octo,
+		entries: make(map[string]*cronEntry),
+	}
+}
+
+// Add registers a job which runs according to the cron expression provided.
+// Both 5-field expressions and 6-field expressions with a leading seconds field are supported,
+// as well as the macros @yearly, @monthly, @weekly, @daily, @hourly and "@every <duration>".
+func (c *Cron) Add(name, spec string, fun func(), opts ...ScheduleOption) error {
+	parsed, err := parseSchedule(spec)
+	if err != nil {
+		return err
+	}
+
+	return c.add(name, spec, parsed, fun, opts)
+}
+
+// Every registers a job which runs at the fixed interval provided.
+func (c *Cron) Every(name string, interval time.Duration, fun func(), opts ...ScheduleOption) error {
+	if interval <= 0 {
+		return fmt.Errorf("%w: interval must be a positive duration", ErrInvalidSchedule)
+	}
+
+	return c.add(name, "@every "+interval.String(), intervalSchedule{interval: interval}, fun, opts)
+}
+
+// Registers a parsed schedule, and arms it for its first run.
+func (c *Cron) add(name, spec string, parsed schedule, fun func(), opts []ScheduleOption) error {
+	// throw error if function provided is invalid
+	if fun == nil {
+		return ErrNilFunction
+	}
+
+	// throw error if pool is closed
+	if c.octopus.workerPool.isClosed() {
+		return ErrInvalidPoolState
+	}
+
+	entry := &cronEntry{
+		ScheduleInfo: ScheduleInfo{Name: name, Spec: spec},
+		schedule:     parsed,
+		fun:          fun,
+	}
+	for _, opt := range opts {
+		opt(entry)
+	}
+
+	// the time zone set in the expression takes precedence
+	if cron, ok := parsed.(*cronSpec); ok && cron.location == nil {
+		zoned := *cron
+		zoned.location = entry.location
+		entry.schedule = &zoned
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.entries[name]; ok {
+		return ErrScheduleExists
+	}
+	c.entries[name] = entry
+
+	entry.Next = entry.schedule.next(c.octopus.clock.Now())
+	c.arm(entry)
+
+	c.octopus.logger.Info("added schedule", "schedule", name, "spec", spec, "next", entry.Next)
+	return nil
+}
+
+// Schedules returns the state of every schedule, ordered by name.
+func (c *Cron) Schedules() []ScheduleInfo {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	schedules := make([]ScheduleInfo, 0, len(c.entries))
+	for _, entry := range c.entries {
+		schedules = append(schedules, entry.ScheduleInfo)
+	}
+
+	sort.Slice(schedules, func(i, j int) bool {
+		return schedules[i].Name < schedules[j].Name
+	})
+	return schedules
+}
+
+// Pause stops a schedule from starting new runs, active runs are not interrupted.
+func (c *Cron) Pause(name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[name]
+	if !ok {
+		return ErrScheduleNotFound
+	}
+
+	entry.Paused = true
+	entry.Next = time.Time{}
+	entry.Queued = 0
+	c.disarm(entry)
+
+	c.octopus.logger.Info("paused schedule", "schedule", name)
+	return nil
+}
+
+// Resume lets a paused schedule start new runs again, starting with the next time it is due.
+func (c *Cron) Resume(name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[name]
+	if !ok {
+		return ErrScheduleNotFound
+	}
+	if !entry.Paused {
+		return nil
+	}
+
+	entry.Paused = false
+	entry.Next = entry.schedule.next(c.octopus.clock.Now())
+	c.arm(entry)
+
+	c.octopus.logger.Info("resumed schedule", "schedule", name, "next", entry.Next)
+	return nil
+}
+
+// Remove unregisters a schedule, active runs are not interrupted.
+func (c *Cron) Remove(name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[name]
+	if !ok {
+		return ErrScheduleNotFound
+	}
+
+	entry.removed = true
+	c.disarm(entry)
+	delete(c.entries, name)
+
+	c.octopus.logger.Info("removed schedule", "schedule", name)
+	return nil
+}
+
+// Stop removes every schedule, active runs are not interrupted.
+func (c *Cron) Stop() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for name, entry := range c.entries {
+		entry.removed = true
+		c.disarm(entry)
+		delete(c.entries, name)
+	}
+}
+
+// Sets the timer to fire once the next run is due, the lock must be held.
+func (c *Cron) arm(entry *cronEntry) {
+	c.disarm(entry)
+	if entry.Next.IsZero() {
+		return
+	}
+
+	delay := entry.Next.Sub(c.octopus.clock.Now())
+	if entry.jitter > 0 {
+		delay += time.Duration(rand.Int63n(int64(entry.jitter) + 1))
+	}
+
+	generation := entry.generation
+	entry.timer = c.octopus.clock.AfterFunc(delay, func() {
+		c.fire(entry, generation)
+	})
+}
+
+// Stops the timer, the lock must be held.
+func (c *Cron) disarm(entry *cronEntry) {
+	// timers which already fired are ignored once the generation changes
+	entry.generation++
+	if entry.timer != nil {
+		entry.timer.Stop()
+		entry.timer = nil
+	}
+}
+
+// Starts a run which is due, applying the overlap policy, and arms the schedule for the next run.
+func (c *Cron) fire(entry *cronEntry, generation uint64) {
+	c.mu.Lock()
+
+	if entry.removed || entry.Paused || entry.generation != generation {
+		c.mu.Unlock()
+		return
+	}
+
+	// the octopus does not accept jobs anymore
+	if c.octopus.workerPool.isClosed() {
+		c.octopus.logger.Info("stopping schedule, the pool is closed", "schedule", entry.Name)
+		entry.Next = time.Time{}
+		c.disarm(entry)
+		c.mu.Unlock()
+		return
+	}
+
+	// compute the next run from the planned time, so that jitter and delays do not add up
+	now := c.octopus.clock.Now()
+	next := entry.schedule.next(entry.Next)
+	if !next.IsZero() && next.Before(now) {
+		// runs which were missed are not caught up on
+		next = entry.schedule.next(now)
+	}
+	entry.Next = next
+	c.arm(entry)
+
+	start := c.admit(entry)
+	c.mu.Unlock()
+
+	if start {
+		c.run(entry)
+	}
+}
+
+// Reports whether a run can start according to the overlap policy, the lock must be held.
+func (c *Cron) admit(entry *cronEntry) bool {
+	if entry.Running > 0 {
+		switch entry.overlapPolicy {
+		case OverlapQueue:
+			c.octopus.logger.Debug("queueing run, previous run is still active", "schedule", entry.Name, "running", entry.Running)
+			entry.Queued++
+			return false
+		case OverlapConcurrent:
+		default:
+			c.octopus.logger.Info("skipping run, previous run is still active", "schedule", entry.Name, "running", entry.Running)
+			entry.Skipped++
+			return false
+		}
+	}
+
+	entry.Running++
+	entry.Runs++
+	entry.LastRun = c.octopus.clock.Now()
+	return true
+}
+
+// Handles a run admitted by admit.
+func (c *Cron) run(entry *cronEntry) {
+	job := Job{function: entry.fun, name: entry.Name}
+	job.handle = newJobHandle(job.name)
+	job.handle.notify = func() {
+		c.finish(entry, job.handle.err)
+	}
+
+	if err := c.octopus.dispatch(job); err != nil {
+		c.octopus.logger.Warn("cannot handle run", "schedule", entry.Name, "error", err)
+		job.handle.finish(nil, err)
+	}
+}
+
+// Records a run which finished, and starts a queued run if there is one.
+func (c *Cron) finish(entry *cronEntry, err error) {
+	c.mu.Lock()
+
+	entry.Running--
+	entry.LastErr = err
+
+	start := false
+	if entry.Queued > 0 && !entry.removed && !entry.Paused {
+		entry.Queued--
+		entry.Running++
+		entry.Runs++
+		entry.LastRun = c.octopus.clock.Now()
+		start = true
+	}
+	c.mu.Unlock()
+
+	if start {
+		c.run(entry)
+	}
+}
@@ -0,0 +1,180 @@
+// Copyright 2021 Aadhav Vignesh
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octopool
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Returns a cron using a fake clock, and the clock.
+func newFakeCron(t *testing.T, capacity int) (*Cron, *FakeClock) {
+	t.Helper()
+
+	clock := NewFakeClock(fakeStart)
+	testOctopus := NewOctopusWithOptions(capacity, queueCapacity, WithClock(clock))
+	t.Cleanup(testOctopus.Close)
+
+	return NewCron(testOctopus), clock
+}
+
+// Test for checking that jobs run at a fixed interval.
+func TestCronEvery(t *testing.T) {
+	cron, clock := newFakeCron(t, 1)
+
+	var runs int32
+	assert.NoError(t, cron.Every("tick", time.Minute, func() { atomic.AddInt32(&runs, 1) }))
+
+	clock.Advance(59 * time.Second)
+	cron.octopus.Wait()
+	assert.Equal(t, int32(0), atomic.LoadInt32(&runs))
+
+	for i := 0; i < 3; i++ {
+		clock.Advance(time.Minute)
+		cron.octopus.Wait()
+	}
+
+	assert.Equal(t, int32(3), atomic.LoadInt32(&runs))
+
+	schedules := cron.Schedules()
+	if assert.Len(t, schedules, 1) {
+		assert.Equal(t, "tick", schedules[0].Name)
+		assert.Equal(t, "@every 1m0s", schedules[0].Spec)
+		assert.Equal(t, 3, schedules[0].Runs)
+		assert.Equal(t, fakeStart.Add(4*time.Minute), schedules[0].Next)
+		assert.Equal(t, fakeStart.Add(3*time.Minute), schedules[0].LastRun)
+	}
+}
+
+// Test for checking that cron expressions are evaluated in the schedule's time zone.
+func TestCronLocation(t *testing.T) {
+	cron, clock := newFakeCron(t, 1)
+
+	var runs int32
+	assert.NoError(t, cron.Add("nine", "0 9 * * *", func() { atomic.AddInt32(&runs, 1) },
+		WithLocation(time.FixedZone("UTC+2", 2*60*60)),
+	))
+	assert.True(t, fakeStart.Add(7*time.Hour).Equal(cron.Schedules()[0].Next))
+
+	clock.Advance(7 * time.Hour)
+	cron.octopus.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&runs))
+	assert.True(t, fakeStart.Add(31*time.Hour).Equal(cron.Schedules()[0].Next))
+}
+
+// Test for checking that runs are delayed by the jitter, without moving the schedule.
+func TestCronJitter(t *testing.T) {
+	cron, clock := newFakeCron(t, 1)
+
+	var runs int32
+	assert.NoError(t, cron.Every("tick", time.Minute, func() { atomic.AddInt32(&runs, 1) }, WithJitter(30*time.Second)))
+
+	clock.Advance(90 * time.Second)
+	cron.octopus.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&runs))
+	assert.Equal(t, fakeStart.Add(2*time.Minute), cron.Schedules()[0].Next)
+	assert.False(t, cron.Schedules()[0].LastRun.Before(fakeStart.Add(time.Minute)))
+}
+
+// Test for checking the overlap policies when a run is due while the previous run is still active.
+func TestCronOverlapPolicy(t *testing.T) {
+	tests := []struct {
+		policy  OverlapPolicy
+		runs    int
+		skipped int
+	}{
+		{OverlapSkip, 1, 1},
+		{OverlapQueue, 2, 0},
+		{OverlapConcurrent, 2, 0},
+	}
+
+	for _, test := range tests {
+		cron, clock := newFakeCron(t, 2)
+
+		release := make(chan struct{})
+		assert.NoError(t, cron.Every("slow", time.Minute, func() { <-release }, WithOverlapPolicy(test.policy)))
+
+		clock.Advance(time.Minute)
+		clock.Advance(time.Minute)
+
+		info := cron.Schedules()[0]
+		assert.Equal(t, test.skipped, info.Skipped, "policy %d", test.policy)
+		if test.policy == OverlapQueue {
+			assert.Equal(t, 1, info.Running)
+			assert.Equal(t, 1, info.Queued)
+		}
+
+		close(release)
+		cron.octopus.Wait()
+
+		info = cron.Schedules()[0]
+		assert.Equal(t, test.runs, info.Runs, "policy %d", test.policy)
+		assert.Equal(t, 0, info.Running)
+		assert.Equal(t, 0, info.Queued)
+	}
+}
+
+// Test for checking that schedules can be paused, resumed and removed.
+func TestCronPauseResumeRemove(t *testing.T) {
+	cron, clock := newFakeCron(t, 1)
+
+	var runs int32
+	assert.NoError(t, cron.Add("minutely", "* * * * *", func() { atomic.AddInt32(&runs, 1) }, WithLocation(time.UTC)))
+	assert.ErrorIs(t, cron.Add("minutely", "* * * * *", func() {}), ErrScheduleExists)
+	assert.ErrorIs(t, cron.Add("invalid", "* * *", func() {}), ErrInvalidSchedule)
+	assert.ErrorIs(t, cron.Add("nil", "* * * * *", nil), ErrNilFunction)
+
+	assert.NoError(t, cron.Pause("minutely"))
+	assert.True(t, cron.Schedules()[0].Paused)
+	assert.True(t, cron.Schedules()[0].Next.IsZero())
+
+	clock.Advance(5 * time.Minute)
+	cron.octopus.Wait()
+	assert.Equal(t, int32(0), atomic.LoadInt32(&runs))
+
+	assert.NoError(t, cron.Resume("minutely"))
+	assert.Equal(t, fakeStart.Add(6*time.Minute), cron.Schedules()[0].Next)
+
+	clock.Advance(time.Minute)
+	cron.octopus.Wait()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&runs))
+
+	assert.NoError(t, cron.Remove("minutely"))
+	assert.Empty(t, cron.Schedules())
+	assert.ErrorIs(t, cron.Remove("minutely"), ErrScheduleNotFound)
+	assert.ErrorIs(t, cron.Pause("minutely"), ErrScheduleNotFound)
+
+	clock.Advance(time.Minute)
+	cron.octopus.Wait()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&runs))
+}
+
+// Test for checking that the error of the last run is reported.
+func TestCronLastErr(t *testing.T) {
+	cron, clock := newFakeCron(t, 1)
+
+	assert.NoError(t, cron.Every("panicking", time.Minute, func() { panic("boom") }))
+
+	clock.Advance(time.Minute)
+	cron.octopus.Wait()
+
+	assert.True(t, errors.Is(cron.Schedules()[0].LastErr, ErrJobPanicked))
+}
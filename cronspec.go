@@ -0,0 +1,336 @@
+// Copyright 2021 Aadhav Vignesh
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octopool
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidSchedule is the error raised when a cron expression or an interval is invalid.
+var ErrInvalidSchedule = errors.New("invalid schedule")
+
+// schedule is an interface for representing when a recurring job runs.
+type schedule interface {
+	// next returns the first time after t at which the job runs, the zero time if there is none.
+	next(t time.Time) time.Time
+}
+
+// intervalSchedule is a struct for representing a job which runs at a fixed interval.
+type intervalSchedule struct {
+	interval time.Duration // time between two runs
+}
+
+// Returns the time one interval after t.
+func (s intervalSchedule) next(t time.Time) time.Time {
+	return t.Add(s.interval)
+}
+
+// cronSpec is a struct for representing a parsed cron expression.
+// Every field is a bit set, where bit n is set if the field matches the value n.
+type cronSpec struct {
+	second   uint64         // seconds, 0-59
+	minute   uint64         // minutes, 0-59
+	hour     uint64         // hours, 0-23
+	dom      uint64         // days of the month, 1-31
+	month    uint64         // months, 1-12
+	dow      uint64         // days of the week, 0-6 starting on Sunday
+	domStar  bool           // reports whether the day of the month is unrestricted
+	dowStar  bool           // reports whether the day of the week is unrestricted
+	location *time.Location // time zone the expression is evaluated in, nil until set
+}
+
+// cronField is a struct for representing the bounds and names of a cron field.
+type cronField struct {
+	name  string         // name of the field, used in errors
+	min   int            // smallest value
+	max   int            // largest value
+	names map[string]int // names accepted instead of values
+}
+
+// pre-defined cron fields
+var (
+	secondField = cronField{name: "second", min: 0, max: 59}
+	minuteField = cronField{name: "minute", min: 0, max: 59}
+	hourField   = cronField{name: "hour", min: 0, max: 23}
+	domField    = cronField{name: "day of month", min: 1, max: 31}
+	monthField  = cronField{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// 7 is accepted as Sunday, and folded onto 0
+	dowField = cronField{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+// pre-defined cron macros
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 0 1 1 *",
+	"@annually": "0 0 0 1 1 *",
+	"@monthly":  "0 0 0 1 * *",
+	"@weekly":   "0 0 0 * * 0",
+	"@daily":    "0 0 0 * * *",
+	"@midnight": "0 0 0 * * *",
+	"@hourly":   "0 0 * * * *",
+}
+
+// Parses a schedule: a 5-field cron expression (minute, hour, day of month, month, day of week),
+// a 6-field cron expression with a leading seconds field, a macro like @daily, or "@every <duration>".
+// Cron expressions may start with "CRON_TZ=<zone> " or "TZ=<zone> " to set their time zone.
+func parseSchedule(spec string) (schedule, error) {
+	spec = strings.TrimSpace(spec)
+
+	var location *time.Location
+	if strings.HasPrefix(spec, "CRON_TZ=") || strings.HasPrefix(spec, "TZ=") {
+		zone, rest, _ := strings.Cut(spec, " ")
+		_, name, _ := strings.Cut(zone, "=")
+
+		var err error
+		if location, err = time.LoadLocation(name); err != nil {
+			return nil, fmt.Errorf("%w: %q: %v", ErrInvalidSchedule, spec, err)
+		}
+		spec = strings.TrimSpace(rest)
+	}
+
+	if interval, ok := strings.CutPrefix(spec, "@every "); ok {
+		d, err := time.ParseDuration(strings.TrimSpace(interval))
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("%w: %q: interval must be a positive duration", ErrInvalidSchedule, spec)
+		}
+		return intervalSchedule{interval: d}, nil
+	}
+
+	expr := spec
+	if macro, ok := cronMacros[strings.ToLower(spec)]; ok {
+		expr = macro
+	}
+
+	fields := strings.Fields(expr)
+	switch len(fields) {
+	case 5:
+		// run at the start of the minute
+		fields = append([]string{"0"}, fields...)
+	case 6:
+	default:
+		return nil, fmt.Errorf("%w: %q: expected 5 or 6 fields, found %d", ErrInvalidSchedule, spec, len(fields))
+	}
+
+	cron := &cronSpec{location: location}
+	var err error
+	for i, field := range []struct {
+		bits  *uint64
+		field cronField
+	}{
+		{&cron.second, secondField},
+		{&cron.minute, minuteField},
+		{&cron.hour, hourField},
+		{&cron.dom, domField},
+		{&cron.month, monthField},
+		{&cron.dow, dowField},
+	} {
+		if *field.bits, err = parseCronField(fields[i], field.field); err != nil {
+			return nil, fmt.Errorf("%w: %q: %v", ErrInvalidSchedule, spec, err)
+		}
+	}
+
+	cron.domStar = isWildcard(fields[3])
+	cron.dowStar = isWildcard(fields[5])
+
+	// fold Sunday as 7 onto Sunday as 0
+	if cron.dow&(1<<7) != 0 {
+		cron.dow = cron.dow&^(1<<7) | 1
+	}
+
+	return cron, nil
+}
+
+// Checks if a field matches every value.
+func isWildcard(field string) bool {
+	return field == "*" || field == "?"
+}
+
+// Parses a comma-separated list of values, ranges and steps into a bit set.
+func parseCronField(expr string, field cronField) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(expr, ",") {
+		rangeExpr, stepExpr, hasStep := strings.Cut(part, "/")
+
+		low, high := field.min, field.max
+		if !isWildcard(rangeExpr) {
+			lowExpr, highExpr, hasHigh := strings.Cut(rangeExpr, "-")
+
+			var err error
+			if low, err = parseCronValue(lowExpr, field); err != nil {
+				return 0, err
+			}
+			switch {
+			case hasHigh:
+				if high, err = parseCronValue(highExpr, field); err != nil {
+					return 0, err
+				}
+			case !hasStep:
+				// a single value
+				high = low
+			}
+		}
+
+		if low > high {
+			return 0, fmt.Errorf("%s: range %q is reversed", field.name, part)
+		}
+
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepExpr); err != nil || step <= 0 {
+				return 0, fmt.Errorf("%s: step %q must be a positive number", field.name, stepExpr)
+			}
+		}
+
+		for value := low; value <= high; value += step {
+			bits |= 1 << uint(value)
+		}
+	}
+	return bits, nil
+}
+
+// Parses a single value or name of the field.
+func parseCronValue(expr string, field cronField) (int, error) {
+	if value, ok := field.names[strings.ToLower(expr)]; ok {
+		return value, nil
+	}
+
+	value, err := strconv.Atoi(expr)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %q is not a valid value", field.name, expr)
+	}
+	if value < field.min || value > field.max {
+		return 0, fmt.Errorf("%s: %d is out of the range %d-%d", field.name, value, field.min, field.max)
+	}
+	return value, nil
+}
+
+// Checks if the bit for the value is set.
+func matches(bits uint64, value int) bool {
+	return bits&(1<<uint(value)) != 0
+}
+
+// Checks if the day of t matches the expression.
+// If both the day of the month and the day of the week are restricted, either of them has to match.
+func (s *cronSpec) dayMatches(t time.Time) bool {
+	domMatch := matches(s.dom, t.Day())
+	dowMatch := matches(s.dow, int(t.Weekday()))
+
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// Returns the first time after t which matches the expression, in the expression's time zone.
+// Returns the zero time if no time matches within five years, e.g. for February 30th.
+func (s *cronSpec) next(t time.Time) time.Time {
+	location := s.location
+	if location == nil {
+		location = time.Local
+	}
+
+	// start at the next whole second
+	t = t.In(location)
+	t = t.Add(time.Second - time.Duration(t.Nanosecond()))
+
+	// the fields below the one being incremented are reset once
+	added := false
+	yearLimit := t.Year() + 5
+
+wrap:
+	if t.Year() > yearLimit {
+		return time.Time{}
+	}
+
+	for !matches(s.month, int(t.Month())) {
+		if !added {
+			added = true
+			t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, location)
+		}
+		t = t.AddDate(0, 1, 0)
+
+		if t.Month() == time.January {
+			goto wrap
+		}
+	}
+
+	for !s.dayMatches(t) {
+		if !added {
+			added = true
+			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, location)
+		}
+		t = t.AddDate(0, 0, 1)
+
+		// a daylight saving transition may have moved midnight
+		if t.Hour() != 0 {
+			if t.Hour() > 12 {
+				t = t.Add(time.Duration(24-t.Hour()) * time.Hour)
+			} else {
+				t = t.Add(time.Duration(-t.Hour()) * time.Hour)
+			}
+		}
+
+		if t.Day() == 1 {
+			goto wrap
+		}
+	}
+
+	for !matches(s.hour, t.Hour()) {
+		if !added {
+			added = true
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, location)
+		}
+		t = t.Add(time.Hour)
+
+		if t.Hour() == 0 {
+			goto wrap
+		}
+	}
+
+	for !matches(s.minute, t.Minute()) {
+		if !added {
+			added = true
+			t = t.Truncate(time.Minute)
+		}
+		t = t.Add(time.Minute)
+
+		if t.Minute() == 0 {
+			goto wrap
+		}
+	}
+
+	for !matches(s.second, t.Second()) {
+		if !added {
+			added = true
+			t = t.Truncate(time.Second)
+		}
+		t = t.Add(time.Second)
+
+		if t.Second() == 0 {
+			goto wrap
+		}
+	}
+
+	return t
+}
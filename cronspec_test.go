@@ -0,0 +1,102 @@
+// Copyright 2021 Aadhav Vignesh
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octopool
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test for checking the next times of cron expressions.
+func TestCronSpecNext(t *testing.T) {
+	from := time.Date(2024, time.June, 1, 10, 7, 30, 0, time.UTC) // a Saturday
+
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"*/15 * * * *", time.Date(2024, time.June, 1, 10, 15, 0, 0, time.UTC)},
+		{"0 9 * * mon-fri", time.Date(2024, time.June, 3, 9, 0, 0, 0, time.UTC)},
+		{"0 12 * * 7", time.Date(2024, time.June, 2, 12, 0, 0, 0, time.UTC)},
+		{"0 0 13 * FRI", time.Date(2024, time.June, 7, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1,15 * *", time.Date(2024, time.June, 15, 0, 0, 0, 0, time.UTC)},
+		{"30 0 0 1 jan *", time.Date(2025, time.January, 1, 0, 0, 30, 0, time.UTC)},
+		{"*/20 * * * * *", time.Date(2024, time.June, 1, 10, 7, 40, 0, time.UTC)},
+		{"5/20 8 * * * ?", time.Date(2024, time.June, 1, 10, 8, 5, 0, time.UTC)},
+		{"@hourly", time.Date(2024, time.June, 1, 11, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2024, time.June, 2, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC)},
+		{"@every 90s", from.Add(90 * time.Second)},
+		{"CRON_TZ=America/New_York 0 9 * * *", time.Date(2024, time.June, 1, 13, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+
+	for _, test := range tests {
+		parsed, err := parseSchedule(test.spec)
+		if !assert.NoError(t, err, test.spec) {
+			continue
+		}
+
+		// cron expressions without a time zone are evaluated in the local time zone
+		if cron, ok := parsed.(*cronSpec); ok && cron.location == nil {
+			cron.location = time.UTC
+		}
+
+		got := parsed.next(from)
+		assert.True(t, test.want.Equal(got), "%s: expected %v, got %v", test.spec, test.want, got)
+	}
+}
+
+// Test for checking that cron expressions are evaluated in their time zone across daylight saving transitions.
+func TestCronSpecDaylightSaving(t *testing.T) {
+	location, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("time zone database is not available")
+	}
+
+	parsed, err := parseSchedule("0 9 * * *")
+	assert.NoError(t, err)
+	parsed.(*cronSpec).location = location
+
+	// clocks move forward on March 31st 2024 in Berlin
+	next := parsed.next(time.Date(2024, time.March, 30, 12, 0, 0, 0, location))
+	assert.Equal(t, time.Date(2024, time.March, 31, 9, 0, 0, 0, location), next)
+	assert.Equal(t, 7, next.UTC().Hour())
+}
+
+// Test for checking that invalid schedules are rejected.
+func TestCronSpecInvalid(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"* * * * * * *",
+		"61 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"* * * foo *",
+		"@every -1s",
+		"@every soon",
+		"@fortnightly",
+		"TZ=Nowhere/Zone * * * * *",
+	} {
+		_, err := parseSchedule(spec)
+		assert.True(t, errors.Is(err, ErrInvalidSchedule), "%q should be invalid, got %v", spec, err)
+	}
+}
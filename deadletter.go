@@ -0,0 +1,171 @@
+// Copyright 2021 Aadhav Vignesh
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octopool
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrDeadLetterNotFound is the error raised when no dead letter exists with the ID provided.
+var ErrDeadLetterNotFound = errors.New("dead letter not found")
+
+// DeadLetter is a struct for representing a job which failed permanently, after exhausting its retries or panicking.
+type DeadLetter struct {
+	ID          uint64    // identifier of the dead letter, unique within the octopus
+	Job         Job       // the failed job
+	Err         error     // final error of the job
+	Attempts    int       // number of times the job was executed
+	SubmittedAt time.Time // time at which the job was handled
+	FailedAt    time.Time // time at which the job failed permanently
+}
+
+// Holds the jobs which failed permanently, oldest first.
+type deadLetterQueue struct {
+	mu       sync.Mutex    // mutex for locking the dead letters
+	letters  []*DeadLetter // dead letters in the order they failed
+	capacity int           // maximum number of dead letters, zero means no limit
+	nextID   uint64        // identifier of the next dead letter
+}
+
+// Adds a dead letter, evicting the oldest one if the queue is full, and returns the evicted dead letter if any.
+func (dlq *deadLetterQueue) add(letter *DeadLetter) (evicted *DeadLetter) {
+	dlq.mu.Lock()
+	defer dlq.mu.Unlock()
+
+	dlq.nextID++
+	letter.ID = dlq.nextID
+
+	if dlq.capacity > 0 && len(dlq.letters) >= dlq.capacity {
+		evicted = dlq.letters[0]
+		dlq.letters[0] = nil
+		dlq.letters = dlq.letters[1:]
+	}
+	dlq.letters = append(dlq.letters, letter)
+	return evicted
+}
+
+// Removes and returns the dead letter with the ID provided.
+func (dlq *deadLetterQueue) remove(id uint64) (*DeadLetter, bool) {
+	dlq.mu.Lock()
+	defer dlq.mu.Unlock()
+
+	for i, letter := range dlq.letters {
+		if letter.ID == id {
+			dlq.letters = append(dlq.letters[:i], dlq.letters[i+1:]...)
+			return letter, true
+		}
+	}
+	return nil, false
+}
+
+// Puts a removed dead letter back at its position.
+func (dlq *deadLetterQueue) restore(letter *DeadLetter) {
+	dlq.mu.Lock()
+	defer dlq.mu.Unlock()
+
+	i := sort.Search(len(dlq.letters), func(i int) bool {
+		return dlq.letters[i].ID > letter.ID
+	})
+	dlq.letters = append(dlq.letters, nil)
+	copy(dlq.letters[i+1:], dlq.letters[i:])
+	dlq.letters[i] = letter
+}
+
+// Moves the permanently failed job to the dead-letter queue, if it is enabled.
+func (octo *Octopus) deadLetter(job Job, err error) {
+	if octo.deadLetters == nil {
+		return
+	}
+
+	octo.logger.Warn("moving job to the dead-letter queue", "job", job.name, "attempts", job.attempts, "error", err)
+	evicted := octo.deadLetters.add(&DeadLetter{
+		Job:         job,
+		Err:         err,
+		Attempts:    job.attempts,
+		SubmittedAt: job.submittedAt,
+		FailedAt:    octo.clock.Now(),
+	})
+
+	if evicted != nil {
+		octo.logger.Warn("evicting dead letter, dead-letter queue is full", "dead_letter", evicted.ID, "job", evicted.Job.name)
+	}
+}
+
+// DeadLetters returns the jobs which failed permanently, oldest first.
+// Returns nil if the dead-letter queue is not enabled using WithDeadLetterQueue.
+func (octo *Octopus) DeadLetters() []DeadLetter {
+	if octo.deadLetters == nil {
+		return nil
+	}
+
+	octo.deadLetters.mu.Lock()
+	defer octo.deadLetters.mu.Unlock()
+
+	letters := make([]DeadLetter, 0, len(octo.deadLetters.letters))
+	for _, letter := range octo.deadLetters.letters {
+		letters = append(letters, *letter)
+	}
+	return letters
+}
+
+// RequeueDeadLetter removes the dead letter with the ID provided, and handles its job again with no attempts.
+// Returns a new handle for the job, the dead letter is kept if the job could not be handled.
+func (octo *Octopus) RequeueDeadLetter(id uint64) (*JobHandle, error) {
+	if octo.deadLetters == nil {
+		return nil, ErrDeadLetterNotFound
+	}
+
+	// throw error if pool is closed
+	if octo.workerPool.isClosed() {
+		return nil, ErrInvalidPoolState
+	}
+
+	letter, ok := octo.deadLetters.remove(id)
+	if !ok {
+		return nil, ErrDeadLetterNotFound
+	}
+
+	job := letter.Job
+	job.attempts = 0
+	job.handle = newJobHandle(job.name)
+	// the job is handled right away, it counts as submitted again even if it was scheduled
+	job.scheduled = false
+	job.timedOut = false
+
+	octo.logger.Info("requeueing dead letter", "dead_letter", id, "job", job.name)
+	if err := octo.dispatch(job); err != nil {
+		octo.deadLetters.restore(letter)
+		return nil, err
+	}
+
+	return job.handle, nil
+}
+
+// PurgeDeadLetters removes every dead letter, and returns the number of dead letters removed.
+func (octo *Octopus) PurgeDeadLetters() int {
+	if octo.deadLetters == nil {
+		return 0
+	}
+
+	octo.deadLetters.mu.Lock()
+	defer octo.deadLetters.mu.Unlock()
+
+	purged := len(octo.deadLetters.letters)
+	octo.deadLetters.letters = nil
+	return purged
+}
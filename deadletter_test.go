@@ -0,0 +1,150 @@
+// Copyright 2021 Aadhav Vignesh
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octopool
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test for checking that the dead-letter queue is disabled by default.
+func TestDeadLettersDisabled(t *testing.T) {
+	testOctopus := NewOctopus(1, queueCapacity)
+
+	assert.NoError(t, testOctopus.HandleJobErr(func() error { return errors.New("failed") }, "failed"))
+	assert.Error(t, testOctopus.WaitErr())
+
+	assert.Nil(t, testOctopus.DeadLetters())
+	assert.Equal(t, 0, testOctopus.PurgeDeadLetters())
+
+	_, err := testOctopus.RequeueDeadLetter(1)
+	assert.ErrorIs(t, err, ErrDeadLetterNotFound)
+}
+
+// Test for checking that jobs which exhaust their retries or panic are moved to the dead-letter queue.
+func TestDeadLetters(t *testing.T) {
+	testOctopus := NewOctopusWithOptions(1, queueCapacity,
+		WithDeadLetterQueue(0),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}),
+	)
+	errFailed := errors.New("failed")
+	start := time.Now()
+
+	assert.NoError(t, testOctopus.HandleJobErr(func() error { return errFailed }, "failing"))
+	testOctopus.Wait()
+	assert.NoError(t, testOctopus.HandleJob(func() { panic("boom") }, "panicking"))
+	assert.NoError(t, testOctopus.HandleJob(func() {}, "succeeding"))
+	assert.Error(t, testOctopus.WaitErr())
+
+	letters := testOctopus.DeadLetters()
+	if assert.Len(t, letters, 2) {
+		assert.Equal(t, "failing", letters[0].Job.Name())
+		assert.ErrorIs(t, letters[0].Err, errFailed)
+		assert.Equal(t, 3, letters[0].Attempts)
+		assert.False(t, letters[0].SubmittedAt.Before(start))
+		assert.False(t, letters[0].FailedAt.Before(letters[0].SubmittedAt))
+
+		assert.Equal(t, "panicking", letters[1].Job.Name())
+		assert.ErrorIs(t, letters[1].Err, ErrJobPanicked)
+		assert.Equal(t, 1, letters[1].Attempts)
+		assert.NotEqual(t, letters[0].ID, letters[1].ID)
+	}
+
+	assert.Equal(t, 2, testOctopus.PurgeDeadLetters())
+	assert.Empty(t, testOctopus.DeadLetters())
+}
+
+// Test for checking that the oldest dead letters are evicted from a full dead-letter queue.
+func TestDeadLettersCapacity(t *testing.T) {
+	testOctopus := NewOctopusWithOptions(1, queueCapacity, WithDeadLetterQueue(2))
+
+	for _, name := range []string{"first", "second", "third"} {
+		assert.NoError(t, testOctopus.HandleJobErr(func() error { return errors.New("failed") }, name))
+		testOctopus.Wait()
+	}
+
+	letters := testOctopus.DeadLetters()
+	if assert.Len(t, letters, 2) {
+		assert.Equal(t, "second", letters[0].Job.Name())
+		assert.Equal(t, "third", letters[1].Job.Name())
+	}
+}
+
+// Test for checking that dead letters can be requeued individually.
+func TestRequeueDeadLetter(t *testing.T) {
+	testOctopus := NewOctopusWithOptions(1, queueCapacity, WithDeadLetterQueue(0))
+
+	var calls int32
+	assert.NoError(t, testOctopus.HandleJobErr(func() error {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			return errors.New("failed once")
+		}
+		return nil
+	}, "flaky"))
+	assert.NoError(t, testOctopus.HandleJobErr(func() error { return errors.New("failed") }, "failing"))
+	assert.Error(t, testOctopus.WaitErr())
+
+	letters := testOctopus.DeadLetters()
+	assert.Len(t, letters, 2)
+
+	handle, err := testOctopus.RequeueDeadLetter(letters[0].ID)
+	assert.NoError(t, err)
+	_, err = handle.Wait()
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	remaining := testOctopus.DeadLetters()
+	if assert.Len(t, remaining, 1) {
+		assert.Equal(t, letters[1].ID, remaining[0].ID)
+	}
+
+	_, err = testOctopus.RequeueDeadLetter(letters[0].ID)
+	assert.ErrorIs(t, err, ErrDeadLetterNotFound)
+
+	// dead letters are kept if the octopus does not accept jobs anymore
+	testOctopus.Close()
+	_, err = testOctopus.RequeueDeadLetter(letters[1].ID)
+	assert.ErrorIs(t, err, ErrInvalidPoolState)
+	assert.Len(t, testOctopus.DeadLetters(), 1)
+}
+
+// Test for checking that a requeued scheduled job counts as submitted again.
+func TestRequeueDeadLetterScheduled(t *testing.T) {
+	testOctopus := NewOctopusWithOptions(1, queueCapacity, WithDeadLetterQueue(0))
+
+	scheduled, err := testOctopus.HandleJobAfter(time.Millisecond, func() { panic("octopus down") }, "scheduled")
+	assert.NoError(t, err)
+	_, err = scheduled.Wait()
+	assert.Error(t, err)
+	testOctopus.Wait()
+
+	letters := testOctopus.DeadLetters()
+	if !assert.Len(t, letters, 1) {
+		return
+	}
+	handle, err := testOctopus.RequeueDeadLetter(letters[0].ID)
+	assert.NoError(t, err)
+	_, err = handle.Wait()
+	assert.Error(t, err)
+	testOctopus.Wait()
+
+	stats := testOctopus.Stats()
+	assert.Equal(t, uint64(2), stats.Submitted)
+	assert.Equal(t, uint64(2), stats.Panicked)
+}
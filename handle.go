@@ -0,0 +1,81 @@
+// Copyright 2021 Aadhav Vignesh
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octopool
+
+import "sync"
+
+// JobHandle is a struct for representing the outcome of a submitted job.
+// It can be used to wait for the job and collect its result and error.
+type JobHandle struct {
+	done     chan struct{} // closed once the job has finished
+	complete sync.Once     // completes the handle and can be called only once
+	result   interface{}   // value returned by the job
+	err      error         // error returned by the job
+	name     string        // name for the job
+	notify   func()        // called once the job has finished, may be nil
+}
+
+// Returns a handle for a job with the specified name.
+func newJobHandle(name string) *JobHandle {
+	return &JobHandle{
+		done: make(chan struct{}),
+		name: name,
+	}
+}
+
+// Name returns the job's name.
+func (handle *JobHandle) Name() string {
+	return handle.name
+}
+
+// Done returns a channel which is closed once the job has finished.
+func (handle *JobHandle) Done() <-chan struct{} {
+	return handle.done
+}
+
+// Wait blocks until the job has finished and returns its result and error.
+func (handle *JobHandle) Wait() (interface{}, error) {
+	<-handle.done
+	return handle.result, handle.err
+}
+
+// Result blocks until the job has finished and returns its result.
+func (handle *JobHandle) Result() interface{} {
+	<-handle.done
+	return handle.result
+}
+
+// Err blocks until the job has finished and returns its error.
+func (handle *JobHandle) Err() error {
+	<-handle.done
+	return handle.err
+}
+
+// Stores the job's outcome and releases the waiters, nil handles are ignored.
+func (handle *JobHandle) finish(result interface{}, err error) {
+	if handle == nil {
+		return
+	}
+
+	handle.complete.Do(func() {
+		handle.result = result
+		handle.err = err
+		close(handle.done)
+
+		if handle.notify != nil {
+			handle.notify()
+		}
+	})
+}
@@ -0,0 +1,141 @@
+// Copyright 2021 Aadhav Vignesh
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octopool
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test for checking the result of a job which was assigned to a worker immediately.
+func TestSubmitResult(t *testing.T) {
+	testOctopus := NewOctopus(1)
+
+	handle, err := testOctopus.Submit(func() (interface{}, error) {
+		return 42, nil
+	}, "answer")
+	if err != nil {
+		t.Fatalf("Got error while submitting job: %v", err)
+	}
+
+	result, err := handle.Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, 42, result)
+	assert.Equal(t, "answer", handle.Name())
+}
+
+// Test for checking the result of a job which was parked in the job queue first.
+func TestSubmitQueuedResult(t *testing.T) {
+	testOctopus := NewOctopus(1)
+
+	release := make(chan struct{})
+	err := testOctopus.HandleJob(func() { <-release }, "blocker")
+	if err != nil {
+		t.Fatalf("Got error while handling job: %v", err)
+	}
+
+	handle, err := testOctopus.Submit(func() (interface{}, error) {
+		return "queued", nil
+	}, "queued")
+	if err != nil {
+		t.Fatalf("Got error while submitting job: %v", err)
+	}
+
+	// job should be waiting in the queue
+	assert.Equal(t, 1, testOctopus.jobQueue.Len())
+	select {
+	case <-handle.Done():
+		t.Fatal("queued job should not be done.")
+	default:
+	}
+
+	close(release)
+
+	<-handle.Done()
+	assert.Equal(t, "queued", handle.Result())
+	assert.NoError(t, handle.Err())
+}
+
+// Test for checking that the job's error is delivered to the handle.
+func TestSubmitError(t *testing.T) {
+	testOctopus := NewOctopus(1)
+	errJob := errors.New("job failed")
+
+	handle, err := testOctopus.Submit(func() (interface{}, error) {
+		return nil, errJob
+	}, "failing")
+	if err != nil {
+		t.Fatalf("Got error while submitting job: %v", err)
+	}
+
+	assert.Equal(t, errJob, handle.Err())
+	assert.Nil(t, handle.Result())
+}
+
+// Test for checking that a panicking job delivers an error to the handle.
+func TestSubmitPanic(t *testing.T) {
+	testOctopus := NewOctopus(1)
+
+	handle, err := testOctopus.Submit(func() (interface{}, error) {
+		panic("octopus down")
+	}, "panicking")
+	if err != nil {
+		t.Fatalf("Got error while submitting job: %v", err)
+	}
+
+	assert.True(t, errors.Is(handle.Err(), ErrJobPanicked))
+}
+
+// Test for checking that a queued job which is cancelled delivers the context's error.
+func TestSubmitContextCancelled(t *testing.T) {
+	testOctopus := NewOctopus(1)
+
+	release := make(chan struct{})
+	err := testOctopus.HandleJob(func() { <-release }, "blocker")
+	if err != nil {
+		t.Fatalf("Got error while handling job: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	handle, err := testOctopus.SubmitContext(ctx, func(ctx context.Context) (interface{}, error) {
+		return "ran", nil
+	}, "cancelled")
+	if err != nil {
+		t.Fatalf("Got error while submitting job: %v", err)
+	}
+
+	cancel()
+	close(release)
+
+	result, err := handle.Wait()
+
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.Nil(t, result)
+}
+
+// Test for checking submission to a closed pool.
+func TestSubmitClosedPool(t *testing.T) {
+	testOctopus := NewOctopus(1)
+	testOctopus.Close()
+
+	handle, err := testOctopus.Submit(func() (interface{}, error) { return nil, nil })
+
+	assert.Nil(t, handle)
+	assert.Equal(t, ErrInvalidPoolState, err)
+}
@@ -0,0 +1,80 @@
+// Copyright 2021 Aadhav Vignesh
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octopool
+
+// Hooks is a struct for representing functions called at every step of the lifecycle of jobs, nil hooks are skipped.
+// Hooks are called synchronously from the goroutine handling or running the job, and should return quickly.
+type Hooks struct {
+	OnSubmit func(job JobInfo)                       // called when a job is handled, before it is accepted
+	OnQueued func(job JobInfo)                       // called when a job is added to the job queue, possibly after it started
+	OnStart  func(job JobInfo)                       // called before every execution of a job
+	OnFinish func(job JobInfo, err error)            // called after every execution of a job, with its error
+	OnDrop   func(job JobInfo)                       // called for every job dropped by the overflow policy
+	OnPanic  func(job JobInfo, panicErr *PanicError) // called for every job which panics, before OnFinish
+}
+
+// Calls the OnSubmit hooks.
+func (octo *Octopus) hookSubmit(job Job) {
+	for _, hooks := range octo.hooks {
+		if hooks.OnSubmit != nil {
+			hooks.OnSubmit(job.info())
+		}
+	}
+}
+
+// Calls the OnQueued hooks.
+func (octo *Octopus) hookQueued(job Job) {
+	for _, hooks := range octo.hooks {
+		if hooks.OnQueued != nil {
+			hooks.OnQueued(job.info())
+		}
+	}
+}
+
+// Calls the OnStart hooks.
+func (octo *Octopus) hookStart(job Job) {
+	for _, hooks := range octo.hooks {
+		if hooks.OnStart != nil {
+			hooks.OnStart(job.info())
+		}
+	}
+}
+
+// Calls the OnFinish hooks.
+func (octo *Octopus) hookFinish(job Job, err error) {
+	for _, hooks := range octo.hooks {
+		if hooks.OnFinish != nil {
+			hooks.OnFinish(job.info(), err)
+		}
+	}
+}
+
+// Calls the OnDrop hooks.
+func (octo *Octopus) hookDrop(job Job) {
+	for _, hooks := range octo.hooks {
+		if hooks.OnDrop != nil {
+			hooks.OnDrop(job.info())
+		}
+	}
+}
+
+// Calls the OnPanic hooks.
+func (octo *Octopus) hookPanic(job Job, panicErr *PanicError) {
+	for _, hooks := range octo.hooks {
+		if hooks.OnPanic != nil {
+			hooks.OnPanic(job.info(), panicErr)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright 2021 Aadhav Vignesh
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octopool
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Returns hooks recording the lifecycle events of every job, and a function returning the events of a job.
+func recordingHooks() (Hooks, func(name string) []string) {
+	var mu sync.Mutex
+	events := make(map[string][]string)
+	record := func(job JobInfo, event string) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		events[job.Name] = append(events[job.Name], event)
+	}
+
+	hooks := Hooks{
+		OnSubmit: func(job JobInfo) { record(job, "submit") },
+		OnQueued: func(job JobInfo) { record(job, "queued") },
+		OnStart:  func(job JobInfo) { record(job, fmt.Sprint("start ", job.Attempts)) },
+		OnFinish: func(job JobInfo, err error) { record(job, fmt.Sprint("finish ", err)) },
+		OnDrop:   func(job JobInfo) { record(job, "drop") },
+		OnPanic:  func(job JobInfo, panicErr *PanicError) { record(job, fmt.Sprint("panic ", panicErr.Value)) },
+	}
+
+	return hooks, func(name string) []string {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return events[name]
+	}
+}
+
+// Test for checking the hooks called for queued, dropped and panicking jobs.
+func TestHooks(t *testing.T) {
+	hooks, eventsOf := recordingHooks()
+	testOctopus, release := newBusyOctopus(t, 1, WithOverflowPolicy(OverflowDropNewest), WithHooks(hooks))
+
+	assert.NoError(t, testOctopus.HandleJob(func() { panic("octopus down") }, "queued"))
+	assert.NoError(t, testOctopus.HandleJob(func() {}, "dropped"))
+
+	close(release)
+	testOctopus.Wait()
+
+	assert.Equal(t, []string{"submit", "queued", "start 1", "panic octopus down", "finish job: queued panicked: octopus down"}, eventsOf("queued"))
+	assert.Equal(t, []string{"submit", "drop"}, eventsOf("dropped"))
+	assert.Equal(t, []string{"submit", "start 1", "finish <nil>"}, eventsOf("blocker"))
+}
+
+// Test for checking that hooks are called for every attempt of a retried job, and that several hooks can be added.
+func TestHooksRetry(t *testing.T) {
+	hooks, eventsOf := recordingHooks()
+	finished := 0
+	testOctopus := NewOctopusWithOptions(1, queueCapacity,
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 2}),
+		WithHooks(hooks),
+		WithHooks(Hooks{OnFinish: func(job JobInfo, err error) { finished++ }}),
+	)
+
+	assert.True(t, testOctopus.TrySubmit(NewJobErr(func() error { return ErrJobTimeout }).WithName("retried")))
+	testOctopus.Wait()
+
+	assert.Equal(t, []string{"submit", "start 1", "finish job timed out", "start 2", "finish job timed out"}, eventsOf("retried"))
+	assert.Equal(t, 2, finished)
+}
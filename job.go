@@ -14,12 +14,29 @@
 
 package octopool
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+	"time"
+)
 
 // Job is a struct for representing an executable job.
 type Job struct {
-	function func() // the job's function
-	name     string // name for the job
+	function    func()                                         // the job's function
+	ctxFunction func(ctx context.Context) (interface{}, error) // the job's context-aware function
+	ctx         context.Context                                // context the job was submitted with
+	handle      *JobHandle                                     // handle which receives the job's outcome
+	name        string                                         // name for the job
+	priority    int                                            // priority for the job, higher runs first
+	enqueuedAt  time.Time                                      // time at which the job entered the queue
+	sequence    uint64                                         // order in which the job entered the queue
+	retry       *RetryPolicy                                   // retry policy for the job, overrides the octopus' policy
+	attempts    int                                            // number of times the job has been executed
+	submittedAt time.Time                                      // time at which the job was handled
+	timeout     time.Duration                                  // timeout for the job, overrides the octopus' timeout
+	trace       JobTrace                                       // traces the job, nil if tracing is disabled
+	scheduled   bool                                           // the job was counted as submitted when it was scheduled
+	timedOut    bool                                           // an attempt of the job outlived its timeout
 }
 
 // Formats Job struct.
@@ -27,9 +44,66 @@ func (job Job) String() string {
 	return fmt.Sprintf("job: %s\n", job.name)
 }
 
-// Returns the job's function.
-func (job *Job) getJob() func() {
-	return job.function
+// Name returns the job's name.
+func (job Job) Name() string {
+	return job.name
+}
+
+// WithName returns a copy of the job with the name provided.
+func (job Job) WithName(name string) Job {
+	job.name = name
+	return job
+}
+
+// Priority returns the job's priority.
+func (job Job) Priority() int {
+	return job.priority
+}
+
+// WithPriority returns a copy of the job with the priority provided.
+// Priorities only affect the order of jobs in a priority job queue, higher priorities run first.
+func (job Job) WithPriority(priority int) Job {
+	job.priority = priority
+	return job
+}
+
+// Attempts returns the number of times the job has been executed.
+func (job Job) Attempts() int {
+	return job.attempts
+}
+
+// WithRetry returns a copy of the job with the retry policy provided, which overrides the octopus' retry policy.
+func (job Job) WithRetry(policy RetryPolicy) Job {
+	job.retry = &policy
+	return job
+}
+
+// WithTimeout returns a copy of the job with the timeout provided, which overrides the octopus' job timeout.
+// The job's context is cancelled once the timeout expires, and the job fails with ErrJobTimeout.
+func (job Job) WithTimeout(timeout time.Duration) Job {
+	job.timeout = timeout
+	return job
+}
+
+// Checks if the job has a function to execute.
+func (job *Job) isValid() bool {
+	return job.function != nil || job.ctxFunction != nil
+}
+
+// Executes the job, returns the job's result and error.
+func (job *Job) execute() (interface{}, error) {
+	if job.ctxFunction == nil {
+		job.function()
+		return nil, nil
+	}
+
+	// context-aware functions receive the job's context
+	return job.ctxFunction(job.ctx)
+}
+
+// Checks if the job's context is done, in which case the job should not be run.
+func (job *Job) isCancelled() bool {
+	return job.ctx != nil && job.ctx.Err() != nil
 }
 
 // NewJob returns a job with the function wrapped.
@@ -38,3 +112,17 @@ func NewJob(fun func()) Job {
 		function: fun,
 	}
 }
+
+// NewJobErr returns a job with the function wrapped, for functions which can fail.
+func NewJobErr(fun func() error) Job {
+	if fun == nil {
+		return Job{}
+	}
+
+	return Job{
+		ctxFunction: func(ctx context.Context) (interface{}, error) {
+			return nil, fun()
+		},
+		ctx: context.Background(),
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright 2021 Aadhav Vignesh
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octopool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// JobError is a struct for representing the error returned by a job, attributed to the job's name.
+type JobError struct {
+	Name     string // name of the job
+	Err      error  // error returned by the job
+	Attempts int    // number of times the job was executed
+}
+
+// Formats JobError struct.
+func (err *JobError) Error() string {
+	return fmt.Sprintf("job: %s: %v", err.Name, err.Err)
+}
+
+// Unwrap returns the job's error.
+func (err *JobError) Unwrap() error {
+	return err.Err
+}
+
+// HandleJobErr assigns a job which can fail to a worker if workers are available, else, adds to the job queue.
+// The job's error is collected and reported by WaitErr.
+func (octo *Octopus) HandleJobErr(fun func() error, name ...string) error {
+	// throw error if function provided is invalid
+	if fun == nil {
+		return ErrNilFunction
+	}
+
+	return octo.HandleJobContext(context.Background(), func(ctx context.Context) error {
+		return fun()
+	}, name...)
+}
+
+// WaitErr waits on workers to finish the jobs, and returns the errors of the jobs which failed since the last call.
+// The errors are joined using errors.Join, and every error is a *JobError attributing it to the job's name.
+// When failing fast, WaitErr also resets the octopus so that new jobs can run again.
+func (octo *Octopus) WaitErr() error {
+	octo.Wait()
+
+	octo.errMu.Lock()
+	defer octo.errMu.Unlock()
+
+	err := errors.Join(octo.jobErrors...)
+	octo.jobErrors = nil
+
+	// start over after a failure
+	if octo.failCtx != nil && octo.failCtx.Err() != nil {
+		octo.failCtx = nil
+	}
+
+	return err
+}
+
+// Records the job's error, and cancels the remaining jobs on the first error when failing fast.
+func (octo *Octopus) recordError(job Job, err error) {
+	octo.errMu.Lock()
+	defer octo.errMu.Unlock()
+
+	octo.jobErrors = append(octo.jobErrors, &JobError{Name: job.name, Err: err, Attempts: job.attempts})
+
+	if octo.failFast {
+		octo.failContext()
+		octo.failCancel()
+	}
+}
+
+// Checks if a job failed while failing fast, in which case the remaining jobs are cancelled.
+func (octo *Octopus) hasFailed() bool {
+	if !octo.failFast {
+		return false
+	}
+
+	octo.errMu.Lock()
+	defer octo.errMu.Unlock()
+
+	return octo.failCtx != nil && octo.failCtx.Err() != nil
+}
+
+// Returns the context which is cancelled on the first error when failing fast, the error lock must be held.
+func (octo *Octopus) failContext() context.Context {
+	if octo.failCtx == nil {
+		octo.failCtx, octo.failCancel = context.WithCancel(context.Background())
+	}
+	return octo.failCtx
+}
+
+// Returns the context for running a job, which is also cancelled on the first error when failing fast.
+func (octo *Octopus) runContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if !octo.failFast || ctx == nil {
+		return ctx, func() {}
+	}
+
+	octo.errMu.Lock()
+	failCtx := octo.failContext()
+	octo.errMu.Unlock()
+
+	ctx, cancel := context.WithCancel(ctx)
+	stop := context.AfterFunc(failCtx, cancel)
+
+	return ctx, func() {
+		stop()
+		cancel()
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright 2021 Aadhav Vignesh
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octopool
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Returns the names of the jobs which the aggregated error is attributed to.
+func failedJobNames(err error) []string {
+	var names []string
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var jobErr *JobError
+		if errors.As(e, &jobErr) {
+			names = append(names, jobErr.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Test for checking that WaitErr aggregates the errors of every failed job.
+func TestWaitErr(t *testing.T) {
+	testOctopus := NewOctopus(2)
+	errFirst := errors.New("first failure")
+	errSecond := errors.New("second failure")
+
+	assert.NoError(t, testOctopus.HandleJobErr(func() error { return errFirst }, "first"))
+	assert.NoError(t, testOctopus.HandleJobErr(func() error { return nil }, "ok"))
+	assert.NoError(t, testOctopus.HandleJobContext(context.Background(), func(ctx context.Context) error { return errSecond }, "second"))
+	assert.NoError(t, testOctopus.HandleJob(func() { panic("octopus down") }, "panicking"))
+
+	err := testOctopus.WaitErr()
+
+	assert.True(t, errors.Is(err, errFirst))
+	assert.True(t, errors.Is(err, errSecond))
+	assert.True(t, errors.Is(err, ErrJobPanicked))
+	assert.Equal(t, []string{"first", "panicking", "second"}, failedJobNames(err))
+
+	// errors are reported once
+	assert.NoError(t, testOctopus.WaitErr())
+}
+
+// Test for checking that failing fast skips the remaining queued jobs.
+func TestWaitErrFailFast(t *testing.T) {
+	testOctopus := NewOctopusWithOptions(1, queueCapacity, WithFailFast())
+	errImport := errors.New("import failed")
+
+	var ran int32
+	job := func() error {
+		atomic.AddInt32(&ran, 1)
+		return nil
+	}
+
+	release := make(chan struct{})
+	assert.NoError(t, testOctopus.HandleJobErr(func() error {
+		<-release
+		return errImport
+	}, "import 1"))
+	assert.NoError(t, testOctopus.HandleJobErr(job, "import 2"))
+	handle, err := testOctopus.Submit(func() (interface{}, error) { return nil, job() }, "import 3")
+	assert.NoError(t, err)
+
+	close(release)
+	err = testOctopus.WaitErr()
+
+	assert.True(t, errors.Is(err, errImport))
+	assert.Equal(t, []string{"import 1"}, failedJobNames(err))
+	assert.Equal(t, int32(0), atomic.LoadInt32(&ran), "queued jobs should be skipped.")
+	assert.Equal(t, ErrJobCancelled, handle.Err())
+
+	// the octopus runs jobs again after WaitErr
+	assert.NoError(t, testOctopus.HandleJobErr(job, "import 4"))
+	assert.NoError(t, testOctopus.WaitErr())
+	assert.Equal(t, int32(1), atomic.LoadInt32(&ran))
+}
+
+// Test for checking that failing fast cancels the context of running jobs.
+func TestWaitErrFailFastCancelsRunning(t *testing.T) {
+	testOctopus := NewOctopusWithOptions(2, queueCapacity, WithFailFast())
+	errImport := errors.New("import failed")
+
+	started := make(chan struct{})
+	assert.NoError(t, testOctopus.HandleJobContext(context.Background(), func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		return nil
+	}, "long import"))
+
+	<-started
+	assert.NoError(t, testOctopus.HandleJobErr(func() error { return errImport }, "failing import"))
+
+	// WaitErr returns once the long job observed the cancellation
+	err := testOctopus.WaitErr()
+
+	assert.True(t, errors.Is(err, errImport))
+}
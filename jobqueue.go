@@ -14,21 +14,25 @@
 
 package octopool
 
-import (
-	"errors"
-)
+import "sync"
 
-// JobQueue is a struct for representing a queue which holds pending jobs.
+// JobQueue is a struct for representing a FIFO queue which holds pending jobs.
+// It is safe for concurrent use.
 type JobQueue struct {
-	jobQueue  []Job // job queue
-	totalJobs int   // total jobs present in the queue
-	capacity  int   // total capacity of the job queue
+	jobQueue  []Job      // job queue
+	totalJobs int        // total jobs present in the queue
+	capacity  int        // total capacity of the job queue
+	closed    bool       // represents if the queue accepts new jobs
+	mu        sync.Mutex // mutex for locking
 }
 
 // Helper functions:
 
 // IsNotEmpty checks if the job queue is empty or not.
 func (jobQueue *JobQueue) IsNotEmpty() bool {
+	jobQueue.mu.Lock()
+	defer jobQueue.mu.Unlock()
+
 	return jobQueue.totalJobs > 0
 }
 
@@ -45,14 +49,29 @@ func (jobQueue *JobQueue) QueueCapacity() int {
 	return jobQueue.capacity
 }
 
-// AddJob adds a job to the job queue.
-func (jobQueue *JobQueue) AddJob(job Job) {
+// AddJob adds a job to the job queue, returns ErrQueueFull if the job queue is full.
+func (jobQueue *JobQueue) AddJob(job Job) error {
+	jobQueue.mu.Lock()
+	defer jobQueue.mu.Unlock()
+
+	if jobQueue.closed {
+		return ErrQueueClosed
+	}
+
+	if jobQueue.totalJobs >= jobQueue.capacity {
+		return ErrQueueFull
+	}
+
 	jobQueue.jobQueue = append(jobQueue.jobQueue, job)
 	jobQueue.totalJobs++
+	return nil
 }
 
 // RemoveJob removes a job from the job queue.
 func (jobQueue *JobQueue) RemoveJob() (Job, error) {
+	jobQueue.mu.Lock()
+	defer jobQueue.mu.Unlock()
+
 	// remove job from the queue if there exists a job in the queue
 	if jobQueue.totalJobs > 0 {
 		job := jobQueue.jobQueue[0]
@@ -65,5 +84,38 @@ func (jobQueue *JobQueue) RemoveJob() (Job, error) {
 	}
 
 	// else return an error
-	return Job{}, errors.New("empty job queue")
+	return Job{}, ErrQueueEmpty
+}
+
+// Queue implementation:
+
+// Push adds a job to the job queue.
+func (jobQueue *JobQueue) Push(job Job) error {
+	return jobQueue.AddJob(job)
+}
+
+// Pop removes a job from the job queue.
+func (jobQueue *JobQueue) Pop() (Job, error) {
+	return jobQueue.RemoveJob()
+}
+
+// Len returns the number of jobs in the job queue.
+func (jobQueue *JobQueue) Len() int {
+	jobQueue.mu.Lock()
+	defer jobQueue.mu.Unlock()
+
+	return jobQueue.totalJobs
+}
+
+// Capacity returns job queue capacity.
+func (jobQueue *JobQueue) Capacity() int {
+	return jobQueue.capacity
+}
+
+// Close stops the job queue from accepting new jobs.
+func (jobQueue *JobQueue) Close() {
+	jobQueue.mu.Lock()
+	defer jobQueue.mu.Unlock()
+
+	jobQueue.closed = true
 }
@@ -57,3 +57,22 @@ func TestRemoveJobWhenEmpty(t *testing.T) {
 		t.Error("Expected a empty queue error.")
 	}
 }
+
+// Test for checking the behavior when a job is added to a full job queue.
+func TestAddJobWhenFull(t *testing.T) {
+	testQueue := NewJobQueue(1)
+
+	err := testQueue.AddJob(NewJob(func() {}))
+	if err != nil {
+		t.Errorf("Got error: %v", err)
+	}
+
+	err = testQueue.AddJob(NewJob(func() {}))
+	if err != ErrQueueFull {
+		t.Errorf("Expected a full queue error, got: %v", err)
+	}
+
+	if testQueue.totalJobs != 1 {
+		t.Error("Mismatch in job count.")
+	}
+}
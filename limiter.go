@@ -0,0 +1,166 @@
+// Copyright 2021 Aadhav Vignesh
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octopool
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// pre-defined backoff ratio for the AIMD algorithm
+const defaultLimitBackoffRatio = 0.9
+
+// pre-defined latency tolerance for the gradient algorithm
+const defaultLimitTolerance = 1.5
+
+// pre-defined smoothing for the gradient algorithm
+const defaultLimitSmoothing = 0.2
+
+// weight of a sample in the long-term latency average of the gradient algorithm
+const longLatencyWeight = 0.01
+
+// LimitAlgorithm represents how the adaptive concurrency limit reacts to the latencies and errors of jobs.
+type LimitAlgorithm int
+
+const (
+	// LimitAIMD grows the limit additively while jobs succeed, and shrinks it multiplicatively
+	// when a job fails or is slower than the latency threshold
+	LimitAIMD LimitAlgorithm = 0
+	// LimitGradient moves the limit with the ratio between the long-term average latency and the
+	// latest latency, so that it shrinks as soon as jobs queue up in the downstream
+	LimitGradient LimitAlgorithm = 1
+)
+
+// AdaptiveLimitConfig is a struct for representing how the number of concurrently running jobs is adapted.
+type AdaptiveLimitConfig struct {
+	Algorithm        LimitAlgorithm // algorithm adapting the limit
+	InitialLimit     int            // limit before any job has finished, defaults to the minimum limit
+	MinLimit         int            // lower bound for the limit, defaults to 1
+	MaxLimit         int            // upper bound for the limit, zero only bounds it by the current pool capacity
+	LatencyThreshold time.Duration  // LimitAIMD: latency above which a job counts as overload, zero only counts errors
+	BackoffRatio     float64        // LimitAIMD: factor applied to the limit on overload, defaults to 0.9
+	Tolerance        float64        // LimitGradient: latency increase tolerated before shrinking, defaults to 1.5
+	Smoothing        float64        // LimitGradient: weight of a new limit against the current one, defaults to 0.2
+}
+
+// adaptiveLimiter is a struct for representing the adaptive concurrency limit.
+type adaptiveLimiter struct {
+	config      AdaptiveLimitConfig // limit configuration
+	limit       float64             // current limit, truncated when used
+	longLatency float64             // long-term average latency in nanoseconds, used by LimitGradient
+	mu          sync.Mutex          // mutex for locking
+}
+
+// Returns a limiter with the defaults applied to the configuration.
+func newAdaptiveLimiter(config AdaptiveLimitConfig) *adaptiveLimiter {
+	if config.MinLimit <= 0 {
+		config.MinLimit = 1
+	}
+	if config.MaxLimit <= 0 {
+		config.MaxLimit = math.MaxInt32
+	}
+	config.MinLimit = min(config.MinLimit, config.MaxLimit)
+	if config.InitialLimit <= 0 {
+		config.InitialLimit = config.MinLimit
+	}
+	if config.BackoffRatio <= 0 || config.BackoffRatio >= 1 {
+		config.BackoffRatio = defaultLimitBackoffRatio
+	}
+	if config.Tolerance < 1 {
+		config.Tolerance = defaultLimitTolerance
+	}
+	if config.Smoothing <= 0 || config.Smoothing > 1 {
+		config.Smoothing = defaultLimitSmoothing
+	}
+
+	return &adaptiveLimiter{
+		config: config,
+		limit:  float64(clamp(config.InitialLimit, config.MinLimit, config.MaxLimit)),
+	}
+}
+
+// Returns the current limit.
+func (l *adaptiveLimiter) currentLimit() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return int(l.limit)
+}
+
+// Adapts the limit to the latency and error of a finished job, returns true if the limit grew.
+// The limit never grows above the pool capacity, which may change with Resize or autoscaling.
+func (l *adaptiveLimiter) observe(latency time.Duration, err error, capacity int) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	before := int(l.limit)
+
+	switch l.config.Algorithm {
+	case LimitGradient:
+		l.observeGradient(float64(latency), err)
+	default:
+		l.observeAIMD(latency, err)
+	}
+
+	maxLimit := min(l.config.MaxLimit, capacity)
+	l.limit = math.Min(math.Max(l.limit, float64(min(l.config.MinLimit, maxLimit))), float64(maxLimit))
+	return int(l.limit) > before
+}
+
+// Grows the limit by one for every limit jobs which succeed, and backs off on overload.
+func (l *adaptiveLimiter) observeAIMD(latency time.Duration, err error) {
+	overloaded := err != nil || (l.config.LatencyThreshold > 0 && latency > l.config.LatencyThreshold)
+	if overloaded {
+		l.limit *= l.config.BackoffRatio
+		return
+	}
+	l.limit += 1 / l.limit
+}
+
+// Moves the limit by the gradient between the long-term average latency and the latency, failed jobs halve the limit.
+func (l *adaptiveLimiter) observeGradient(latency float64, err error) {
+	if l.longLatency == 0 {
+		l.longLatency = latency
+	} else {
+		l.longLatency = l.longLatency*(1-longLatencyWeight) + latency*longLatencyWeight
+	}
+
+	gradient := 0.5
+	if err == nil && latency > 0 {
+		gradient = math.Min(math.Max(l.config.Tolerance*l.longLatency/latency, 0.5), 1)
+	}
+
+	// leave room for a queue of sqrt(limit) jobs, so that the limit can grow while latencies are stable
+	target := l.limit*gradient + math.Sqrt(l.limit)
+	l.limit = l.limit*(1-l.config.Smoothing) + target*l.config.Smoothing
+}
+
+// Adapts the concurrency limit to a finished job, and assigns queued jobs to workers if the limit grew.
+func (octo *Octopus) observeJob(latency time.Duration, err error) {
+	if octo.limiter == nil {
+		return
+	}
+
+	if octo.limiter.observe(latency, err, octo.workerPool.getPoolCapacity()) {
+		octo.processNext()
+	}
+}
+
+// CurrentLimit returns the number of jobs which can run concurrently.
+// It is the pool capacity, unless adaptive limiting is enabled using WithAdaptiveLimit.
+func (octo *Octopus) CurrentLimit() int {
+	return octo.workerPool.getLimit()
+}
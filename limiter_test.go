@@ -0,0 +1,150 @@
+// Copyright 2021 Aadhav Vignesh
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octopool
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test for checking that the AIMD algorithm grows additively and backs off multiplicatively.
+func TestAdaptiveLimiterAIMD(t *testing.T) {
+	limiter := newAdaptiveLimiter(AdaptiveLimitConfig{
+		InitialLimit:     4,
+		MaxLimit:         10,
+		LatencyThreshold: 100 * time.Millisecond,
+	})
+	assert.Equal(t, 4, limiter.currentLimit())
+
+	// the limit grows by one once a limit's worth of jobs succeeded
+	grew := false
+	for i := 0; i < 5; i++ {
+		grew = limiter.observe(10*time.Millisecond, nil, 20) || grew
+	}
+	assert.True(t, grew)
+	assert.Equal(t, 5, limiter.currentLimit())
+
+	// errors and slow jobs shrink the limit
+	assert.False(t, limiter.observe(10*time.Millisecond, errors.New("failed"), 20))
+	assert.Equal(t, 4, limiter.currentLimit())
+	limiter.observe(time.Second, nil, 20)
+	assert.Equal(t, 4, limiter.currentLimit())
+	limiter.observe(time.Second, nil, 20)
+	assert.Equal(t, 3, limiter.currentLimit())
+
+	// the limit stays within its bounds
+	for i := 0; i < 100; i++ {
+		limiter.observe(time.Second, nil, 20)
+	}
+	assert.Equal(t, 1, limiter.currentLimit())
+	for i := 0; i < 1000; i++ {
+		limiter.observe(time.Millisecond, nil, 20)
+	}
+	assert.Equal(t, 10, limiter.currentLimit())
+
+	// a pool capacity below the maximum bounds the limit
+	limiter.observe(time.Millisecond, nil, 6)
+	assert.Equal(t, 6, limiter.currentLimit())
+}
+
+// Test for checking that the gradient algorithm grows while latencies are stable, and shrinks when they rise.
+func TestAdaptiveLimiterGradient(t *testing.T) {
+	limiter := newAdaptiveLimiter(AdaptiveLimitConfig{
+		Algorithm:    LimitGradient,
+		InitialLimit: 10,
+		MaxLimit:     50,
+	})
+
+	for i := 0; i < 100; i++ {
+		limiter.observe(10*time.Millisecond, nil, 50)
+	}
+	assert.Equal(t, 50, limiter.currentLimit())
+
+	// a downstream queueing up multiplies the latencies
+	for i := 0; i < 20; i++ {
+		limiter.observe(200*time.Millisecond, nil, 50)
+	}
+	stressed := limiter.currentLimit()
+	assert.Less(t, stressed, 25)
+
+	// failed jobs shrink the limit further
+	for i := 0; i < 10; i++ {
+		limiter.observe(200*time.Millisecond, errors.New("failed"), 50)
+	}
+	assert.Less(t, limiter.currentLimit(), stressed)
+}
+
+// Test for checking that the adaptive limit bounds the number of running jobs.
+func TestOctopusAdaptiveLimit(t *testing.T) {
+	assert.Equal(t, 10, NewOctopus(10, queueCapacity).CurrentLimit())
+
+	testOctopus := NewOctopusWithOptions(10, 100, WithAdaptiveLimit(AdaptiveLimitConfig{InitialLimit: 3}))
+	assert.Equal(t, 3, testOctopus.CurrentLimit())
+
+	var running, peak int32
+	job := func(err error) func() error {
+		return func() error {
+			current := atomic.AddInt32(&running, 1)
+			for {
+				old := atomic.LoadInt32(&peak)
+				if current <= old || atomic.CompareAndSwapInt32(&peak, old, current) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			atomic.AddInt32(&running, -1)
+			return err
+		}
+	}
+
+	// failing jobs shrink the limit to its minimum
+	for i := 0; i < 30; i++ {
+		assert.NoError(t, testOctopus.HandleJobErr(job(errors.New("failed"))))
+	}
+	assert.Error(t, testOctopus.WaitErr())
+
+	assert.LessOrEqual(t, atomic.LoadInt32(&peak), int32(3))
+	assert.Equal(t, 1, testOctopus.CurrentLimit())
+	assert.Equal(t, 1, testOctopus.AvailableWorkers())
+
+	// succeeding jobs grow the limit again
+	for i := 0; i < 30; i++ {
+		assert.NoError(t, testOctopus.HandleJobErr(job(nil)))
+	}
+	assert.NoError(t, testOctopus.WaitErr())
+
+	assert.Greater(t, testOctopus.CurrentLimit(), 1)
+	assert.LessOrEqual(t, testOctopus.CurrentLimit(), 10)
+}
+
+// Test for checking that the adaptive limit grows up to a capacity raised by Resize.
+func TestOctopusAdaptiveLimitResize(t *testing.T) {
+	testOctopus := NewOctopusWithOptions(2, 10, WithAdaptiveLimit(AdaptiveLimitConfig{InitialLimit: 2}))
+	assert.NoError(t, testOctopus.Resize(10))
+
+	for i := 0; i < 200; i++ {
+		testOctopus.observeJob(time.Millisecond, nil)
+	}
+	assert.Equal(t, 10, testOctopus.CurrentLimit())
+
+	// shrinking the pool bounds the limit again
+	assert.NoError(t, testOctopus.Resize(4))
+	testOctopus.observeJob(time.Millisecond, nil)
+	assert.Equal(t, 4, testOctopus.CurrentLimit())
+}
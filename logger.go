@@ -0,0 +1,74 @@
+// Copyright 2021 Aadhav Vignesh
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octopool
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Logger is an interface for representing a levelled, structured logger.
+// Every message is followed by alternating keys and values, like log/slog, so *slog.Logger implements Logger.
+type Logger interface {
+	// Debug logs per-job messages, e.g. assignments to workers.
+	Debug(msg string, args ...interface{})
+	// Info logs changes to the octopus, e.g. resizing or shutting down.
+	Info(msg string, args ...interface{})
+	// Warn logs jobs which did not run as expected, e.g. dropped or timed out jobs.
+	Warn(msg string, args ...interface{})
+	// Error logs failures, e.g. panicking jobs.
+	Error(msg string, args ...interface{})
+}
+
+// levelEnabler is an interface for representing loggers which report whether a level is enabled, like *slog.Logger.
+type levelEnabler interface {
+	Enabled(ctx context.Context, level slog.Level) bool
+}
+
+// NewSlogLogger returns a logger which writes to the slog handler provided, nil uses slog's default logger.
+func NewSlogLogger(handler slog.Handler) Logger {
+	if handler == nil {
+		return slog.Default()
+	}
+	return slog.New(handler)
+}
+
+// nopLogger is a struct for representing a logger which discards every message.
+type nopLogger struct{}
+
+// Debug discards the message.
+func (nopLogger) Debug(msg string, args ...interface{}) {}
+
+// Info discards the message.
+func (nopLogger) Info(msg string, args ...interface{}) {}
+
+// Warn discards the message.
+func (nopLogger) Warn(msg string, args ...interface{}) {}
+
+// Error discards the message.
+func (nopLogger) Error(msg string, args ...interface{}) {}
+
+// Enabled reports that no level is enabled.
+func (nopLogger) Enabled(ctx context.Context, level slog.Level) bool {
+	return false
+}
+
+// Checks if per-job debug messages are logged, so that their fields are only built when needed.
+func (octo *Octopus) debugEnabled() bool {
+	if enabler, ok := octo.logger.(levelEnabler); ok {
+		return enabler.Enabled(context.Background(), slog.LevelDebug)
+	}
+	return true
+}
@@ -0,0 +1,123 @@
+// Copyright 2021 Aadhav Vignesh
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octopool
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingLogger is a struct for representing a logger which records every message with its level.
+type recordingLogger struct {
+	messages []string   // recorded messages, prefixed with their level
+	mu       sync.Mutex // mutex for locking
+}
+
+// Records the message with its fields.
+func (logger *recordingLogger) record(level, msg string, args []interface{}) {
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+
+	logger.messages = append(logger.messages, fmt.Sprint(level, " ", msg, " ", args))
+}
+
+// Debug records the message.
+func (logger *recordingLogger) Debug(msg string, args ...interface{}) {
+	logger.record("DEBUG", msg, args)
+}
+
+// Info records the message.
+func (logger *recordingLogger) Info(msg string, args ...interface{}) {
+	logger.record("INFO", msg, args)
+}
+
+// Warn records the message.
+func (logger *recordingLogger) Warn(msg string, args ...interface{}) {
+	logger.record("WARN", msg, args)
+}
+
+// Error records the message.
+func (logger *recordingLogger) Error(msg string, args ...interface{}) {
+	logger.record("ERROR", msg, args)
+}
+
+// Test for checking that the octopus is quiet by default.
+func TestLoggerDefault(t *testing.T) {
+	testOctopus := NewOctopus(1, queueCapacity)
+
+	assert.Equal(t, nopLogger{}, testOctopus.logger)
+	assert.False(t, testOctopus.debugEnabled())
+
+	testOctopus = NewOctopusWithOptions(1, queueCapacity, WithLogger(nil))
+	assert.Equal(t, nopLogger{}, testOctopus.logger)
+}
+
+// Test for checking that per-job messages are only logged at the debug level.
+func TestSlogLogger(t *testing.T) {
+	var buffer bytes.Buffer
+	testOctopus := NewOctopusWithOptions(1, queueCapacity,
+		WithLogger(NewSlogLogger(slog.NewTextHandler(&buffer, &slog.HandlerOptions{Level: slog.LevelInfo}))),
+	)
+
+	assert.NoError(t, testOctopus.HandleJob(func() {}, "quiet"))
+	testOctopus.Wait()
+	assert.NoError(t, testOctopus.Resize(2))
+
+	assert.NotContains(t, buffer.String(), "job=quiet")
+	assert.Contains(t, buffer.String(), `level=INFO msg="resizing pool" from=1 to=2 active=0`)
+
+	buffer.Reset()
+	testOctopus = NewOctopusWithOptions(1, queueCapacity,
+		WithLogger(slog.New(slog.NewTextHandler(&buffer, &slog.HandlerOptions{Level: slog.LevelDebug}))),
+	)
+
+	assert.NoError(t, testOctopus.HandleJob(func() {}, "verbose"))
+	testOctopus.Wait()
+
+	assert.Contains(t, buffer.String(), `level=DEBUG msg="assigning job to a worker" job=verbose`)
+}
+
+// Test for checking that custom loggers receive levelled messages with structured fields.
+func TestCustomLogger(t *testing.T) {
+	logger := &recordingLogger{}
+	testOctopus := NewOctopusWithOptions(0, 0,
+		WithLogger(logger),
+		WithOverflowPolicy(OverflowDropNewest),
+	)
+
+	// custom loggers without levels receive debug messages
+	assert.True(t, testOctopus.debugEnabled())
+	assert.Contains(t, logger.messages, fmt.Sprint("WARN invalid pool capacity, using the default pool capacity ", []interface{}{"capacity", 0, "default", defaultPoolCapacity}))
+
+	assert.NoError(t, testOctopus.HandleJob(func() { panic("boom") }, "panicking"))
+	testOctopus.Wait()
+
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+
+	assert.Contains(t, logger.messages, fmt.Sprint("DEBUG assigning job to a worker ", []interface{}{"job", "panicking"}))
+
+	found := false
+	for _, message := range logger.messages {
+		found = found || strings.HasPrefix(message, "ERROR recovered panicking job [job panicking panic boom stack")
+	}
+	assert.True(t, found, "the panic should be logged as an error.")
+}
@@ -0,0 +1,148 @@
+// Copyright 2021 Aadhav Vignesh
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octopool
+
+import (
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultBuckets are the upper bounds in seconds of the buckets of the wait and execution time histograms.
+var DefaultBuckets = []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Metrics is a struct for representing a snapshot of the metrics of an octopus, counters only grow.
+type Metrics struct {
+	Submitted     uint64            // number of jobs accepted by the octopus, every one of them ends with one outcome below
+	Completed     uint64            // number of jobs which succeeded
+	Failed        uint64            // number of jobs which failed with an error, after their retries
+	Panicked      uint64            // number of jobs which panicked
+	Dropped       uint64            // number of jobs dropped by the overflow policy
+	Cancelled     uint64            // number of jobs skipped as their context was done or the pool failed fast, or unscheduled
+	Discarded     uint64            // number of jobs discarded without running when the pool was closed or shut down
+	QueueDepth    int               // number of jobs in the job queue
+	ActiveWorkers int               // number of active workers
+	PoolCapacity  int               // number of workers the pool can accommodate
+	QueueWait     HistogramSnapshot // time between accepting a job and its first execution
+	Execution     HistogramSnapshot // time spent executing jobs, for every attempt
+}
+
+// HistogramSnapshot is a struct for representing a snapshot of a histogram of durations.
+type HistogramSnapshot struct {
+	Buckets []float64 // upper bounds of the buckets in seconds, in increasing order
+	Counts  []uint64  // cumulative number of observations for every bucket
+	Count   uint64    // total number of observations
+	Sum     float64   // sum of the observations in seconds
+}
+
+// histogram is a struct for representing a histogram of durations which is safe for concurrent use.
+type histogram struct {
+	buckets []float64       // upper bounds of the buckets in seconds, in increasing order
+	counts  []atomic.Uint64 // number of observations per bucket, the last bucket is unbounded
+	count   atomic.Uint64   // total number of observations
+	sum     atomic.Int64    // sum of the observations in nanoseconds
+}
+
+// Returns a histogram with the buckets provided.
+func newHistogram(buckets []float64) *histogram {
+	sorted := append([]float64(nil), buckets...)
+	sort.Float64s(sorted)
+
+	return &histogram{
+		buckets: sorted,
+		counts:  make([]atomic.Uint64, len(sorted)+1),
+	}
+}
+
+// Records a duration.
+func (h *histogram) observe(d time.Duration) {
+	bucket := sort.SearchFloat64s(h.buckets, d.Seconds())
+	h.counts[bucket].Add(1)
+	h.count.Add(1)
+	h.sum.Add(int64(d))
+}
+
+// Returns a snapshot with cumulative counts.
+func (h *histogram) snapshot() HistogramSnapshot {
+	snapshot := HistogramSnapshot{
+		Buckets: append([]float64(nil), h.buckets...),
+		Counts:  make([]uint64, len(h.buckets)),
+	}
+
+	var cumulative uint64
+	for i := range h.buckets {
+		cumulative += h.counts[i].Load()
+		snapshot.Counts[i] = cumulative
+	}
+	snapshot.Count = cumulative + h.counts[len(h.buckets)].Load()
+	snapshot.Sum = time.Duration(h.sum.Load()).Seconds()
+
+	return snapshot
+}
+
+// metrics is a struct for representing the counters and histograms of an octopus.
+type metrics struct {
+	submitted atomic.Uint64 // number of jobs accepted by the octopus
+	completed atomic.Uint64 // number of jobs which succeeded
+	failed    atomic.Uint64 // number of jobs which failed with an error
+	panicked  atomic.Uint64 // number of jobs which panicked
+	dropped   atomic.Uint64 // number of jobs dropped by the overflow policy
+	cancelled atomic.Uint64 // number of jobs skipped or unscheduled
+	discarded atomic.Uint64 // number of jobs discarded without running
+	queueWait *histogram    // time between accepting a job and its first execution
+	execution *histogram    // time spent executing jobs
+}
+
+// Returns metrics with histograms using the buckets provided.
+func newMetrics(buckets []float64) *metrics {
+	return &metrics{
+		queueWait: newHistogram(buckets),
+		execution: newHistogram(buckets),
+	}
+}
+
+// Records a job accepted by the octopus, scheduled jobs are recorded once when they are scheduled.
+func (m *metrics) accepted(job Job) {
+	if !job.scheduled {
+		m.submitted.Add(1)
+	}
+}
+
+// Records the outcome of a job which will not run again.
+func (m *metrics) finished(err error) {
+	if err != nil {
+		m.failed.Add(1)
+		return
+	}
+	m.completed.Add(1)
+}
+
+// Metrics returns a snapshot of the octopus' metrics.
+func (octo *Octopus) Metrics() Metrics {
+	return Metrics{
+		Submitted:     octo.metrics.submitted.Load(),
+		Completed:     octo.metrics.completed.Load(),
+		Failed:        octo.metrics.failed.Load(),
+		Panicked:      octo.metrics.panicked.Load(),
+		Dropped:       octo.metrics.dropped.Load(),
+		Cancelled:     octo.metrics.cancelled.Load(),
+		Discarded:     octo.metrics.discarded.Load(),
+		QueueDepth:    octo.jobQueue.Len(),
+		ActiveWorkers: octo.workerPool.getActiveWorkersCount(),
+		PoolCapacity:  octo.workerPool.getPoolCapacity(),
+		QueueWait:     octo.metrics.queueWait.snapshot(),
+		Execution:     octo.metrics.execution.snapshot(),
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright 2021 Aadhav Vignesh
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octopool
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test for checking that the histogram counts are cumulative.
+func TestHistogram(t *testing.T) {
+	h := newHistogram([]float64{1, 0.1})
+
+	h.observe(50 * time.Millisecond)
+	h.observe(500 * time.Millisecond)
+	h.observe(time.Second)
+	h.observe(2 * time.Second)
+
+	snapshot := h.snapshot()
+	assert.Equal(t, []float64{0.1, 1}, snapshot.Buckets)
+	assert.Equal(t, []uint64{1, 3}, snapshot.Counts)
+	assert.Equal(t, uint64(4), snapshot.Count)
+	assert.InDelta(t, 3.55, snapshot.Sum, 1e-9)
+}
+
+// Test for checking that the octopus counts the outcome of every job.
+func TestMetrics(t *testing.T) {
+	testOctopus := NewOctopusWithOptions(2, queueCapacity, WithMetricsBuckets([]float64{0.5}))
+
+	assert.NoError(t, testOctopus.HandleJob(func() {}, "completed"))
+	assert.NoError(t, testOctopus.HandleJob(func() { panic("octopus down") }, "panicked"))
+	assert.True(t, testOctopus.TrySubmit(NewJobErr(func() error { return errors.New("failed") })))
+	testOctopus.Wait()
+
+	metrics := testOctopus.Metrics()
+	assert.Equal(t, uint64(3), metrics.Submitted)
+	assert.Equal(t, uint64(1), metrics.Completed)
+	assert.Equal(t, uint64(1), metrics.Failed)
+	assert.Equal(t, uint64(1), metrics.Panicked)
+	assert.Equal(t, uint64(0), metrics.Dropped)
+	assert.Equal(t, 0, metrics.QueueDepth)
+	assert.Equal(t, 2, metrics.PoolCapacity)
+	assert.Equal(t, []float64{0.5}, metrics.Execution.Buckets)
+	assert.Equal(t, uint64(3), metrics.Execution.Count)
+	assert.Equal(t, uint64(3), metrics.QueueWait.Count)
+}
+
+// Test for checking that jobs dropped by the overflow policy are counted.
+func TestMetricsDropped(t *testing.T) {
+	testOctopus, release := newBusyOctopus(t, 1, WithOverflowPolicy(OverflowDropNewest))
+	defer close(release)
+
+	before := testOctopus.Metrics()
+	assert.NoError(t, testOctopus.HandleJob(func() {}, "queued"))
+	assert.NoError(t, testOctopus.HandleJob(func() {}, "dropped"))
+
+	// the dropped job counts as submitted
+	metrics := testOctopus.Metrics()
+	assert.Equal(t, before.Submitted+2, metrics.Submitted)
+	assert.Equal(t, uint64(1), metrics.Dropped)
+	assert.Equal(t, 1, metrics.QueueDepth)
+	assert.Equal(t, 1, metrics.ActiveWorkers)
+}
+
+// Test for checking that both overflow policies dropping jobs count them as submitted.
+func TestMetricsDroppedSubmitted(t *testing.T) {
+	for _, policy := range []OverflowPolicy{OverflowDropOldest, OverflowDropNewest} {
+		testOctopus, release := newBusyOctopus(t, 1, WithOverflowPolicy(policy))
+
+		assert.NoError(t, testOctopus.HandleJob(func() {}, "job 1"))
+		assert.NoError(t, testOctopus.HandleJob(func() {}, "job 2"))
+		close(release)
+		testOctopus.Wait()
+
+		metrics := testOctopus.Metrics()
+		assert.Equal(t, uint64(3), metrics.Submitted)
+		assert.Equal(t, uint64(2), metrics.Completed)
+		assert.Equal(t, uint64(1), metrics.Dropped)
+	}
+}
+
+// Test for checking that the metrics count cancelled and discarded jobs like the statistics.
+func TestMetricsCancelledDiscarded(t *testing.T) {
+	testOctopus, release := newBusyOctopus(t, queueCapacity)
+	defer close(release)
+
+	scheduled, err := testOctopus.HandleJobAfter(time.Hour, func() {}, "unscheduled")
+	assert.NoError(t, err)
+	assert.True(t, scheduled.Cancel())
+	assert.NoError(t, testOctopus.HandleJob(func() {}, "queued"))
+	assert.Len(t, testOctopus.ShutdownNow(), 1)
+
+	metrics := testOctopus.Metrics()
+	assert.Equal(t, uint64(3), metrics.Submitted)
+	assert.Equal(t, uint64(1), metrics.Cancelled)
+	assert.Equal(t, uint64(1), metrics.Discarded)
+}
+
+// Test for checking that retried jobs are counted once, and that every attempt is timed.
+func TestMetricsRetry(t *testing.T) {
+	testOctopus := NewOctopusWithOptions(1, queueCapacity,
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}),
+	)
+
+	assert.True(t, testOctopus.TrySubmit(NewJobErr(func() error { return errors.New("failed") })))
+	testOctopus.Wait()
+
+	metrics := testOctopus.Metrics()
+	assert.Equal(t, uint64(1), metrics.Submitted)
+	assert.Equal(t, uint64(1), metrics.Failed)
+	assert.Equal(t, uint64(3), metrics.Execution.Count)
+	assert.Equal(t, uint64(1), metrics.QueueWait.Count)
+}
@@ -0,0 +1,145 @@
+// Copyright 2021 Aadhav Vignesh
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octopool
+
+import (
+	"context"
+	"time"
+)
+
+// JobFunc is a function executing a job, jobs which are not context-aware ignore ctx and return no result.
+type JobFunc func(ctx context.Context) (interface{}, error)
+
+// Middleware is a function wrapping the execution of every job.
+type Middleware func(next JobFunc) JobFunc
+
+// JobInfo is a struct for representing the description of a job, received by hooks and middleware.
+type JobInfo struct {
+	Name        string    // name for the job
+	Priority    int       // priority for the job
+	Attempts    int       // number of times the job has been executed, including the current execution
+	SubmittedAt time.Time // time at which the job was handled
+}
+
+// jobInfoKey is the key of the job's description in the context of its execution.
+type jobInfoKey struct{}
+
+// Returns the description of the job.
+func (job Job) info() JobInfo {
+	return JobInfo{
+		Name:        job.name,
+		Priority:    job.priority,
+		Attempts:    job.attempts,
+		SubmittedAt: job.submittedAt,
+	}
+}
+
+// JobInfoFromContext returns the description of the job executing with ctx, for use in middleware.
+func JobInfoFromContext(ctx context.Context) (JobInfo, bool) {
+	info, ok := ctx.Value(jobInfoKey{}).(JobInfo)
+	return info, ok
+}
+
+// Returns a copy of the job whose execution is wrapped by the octopus' middleware, the first middleware being the outermost.
+// Jobs which are not context-aware are executed with a background context.
+func (octo *Octopus) applyMiddleware(job Job) Job {
+	if len(octo.middleware) == 0 {
+		return job
+	}
+
+	next := JobFunc(job.ctxFunction)
+	if next == nil {
+		function := job.function
+		next = func(ctx context.Context) (interface{}, error) {
+			function()
+			return nil, nil
+		}
+	}
+	for i := len(octo.middleware) - 1; i >= 0; i-- {
+		next = octo.middleware[i](next)
+	}
+
+	info := job.info()
+	job.ctxFunction = func(ctx context.Context) (interface{}, error) {
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		return next(context.WithValue(ctx, jobInfoKey{}, info))
+	}
+	return job
+}
+
+// TimeoutMiddleware returns a middleware which cancels the context of jobs running longer than the timeout.
+// Jobs which outlive their timeout fail with ErrJobTimeout; unlike WithJobTimeout, jobs are never abandoned.
+func TimeoutMiddleware(timeout time.Duration) Middleware {
+	return func(next JobFunc) JobFunc {
+		return func(ctx context.Context) (interface{}, error) {
+			ctx, cancel := withTimeout(ctx, timeout)
+			defer cancel()
+
+			result, err := next(ctx)
+			if timedOut(ctx) {
+				return nil, ErrJobTimeout
+			}
+			return result, err
+		}
+	}
+}
+
+// RetryMiddleware returns a middleware which executes failed jobs again according to the policy.
+// Unlike WithRetryPolicy, jobs keep their worker while they wait for their backoff. Panics are never retried.
+func RetryMiddleware(policy RetryPolicy) Middleware {
+	return func(next JobFunc) JobFunc {
+		return func(ctx context.Context) (interface{}, error) {
+			for attempt := 1; ; attempt++ {
+				result, err := next(ctx)
+				if err == nil || attempt >= policy.MaxAttempts || ctx.Err() != nil {
+					return result, err
+				}
+				if policy.Retryable != nil && !policy.Retryable(err) {
+					return result, err
+				}
+
+				timer := time.NewTimer(policy.backoff(attempt))
+				select {
+				case <-timer.C:
+				case <-ctx.Done():
+					timer.Stop()
+					return result, err
+				}
+			}
+		}
+	}
+}
+
+// LoggingMiddleware returns a middleware which logs the start of every job at the debug level,
+// and its end with its duration, at the info level if it succeeded or at the warn level if it failed.
+func LoggingMiddleware(logger Logger) Middleware {
+	return func(next JobFunc) JobFunc {
+		return func(ctx context.Context) (interface{}, error) {
+			info, _ := JobInfoFromContext(ctx)
+			logger.Debug("job started", "job", info.Name, "attempt", info.Attempts)
+
+			start := time.Now()
+			result, err := next(ctx)
+			if err != nil {
+				logger.Warn("job failed", "job", info.Name, "attempt", info.Attempts, "duration", time.Since(start), "error", err)
+			} else {
+				logger.Info("job finished", "job", info.Name, "attempt", info.Attempts, "duration", time.Since(start))
+			}
+			return result, err
+		}
+	}
+}
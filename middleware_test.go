@@ -0,0 +1,203 @@
+// Copyright 2021 Aadhav Vignesh
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octopool
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Returns a middleware recording its name before and after the execution of every job.
+func namedMiddleware(name string, record func(event string)) Middleware {
+	return func(next JobFunc) JobFunc {
+		return func(ctx context.Context) (interface{}, error) {
+			record(name + " before")
+			result, err := next(ctx)
+			record(name + " after")
+			return result, err
+		}
+	}
+}
+
+// Test for checking that middleware wraps every job in order, and receives the job's description.
+func TestMiddleware(t *testing.T) {
+	var mu sync.Mutex
+	var events []string
+	record := func(event string) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		events = append(events, event)
+	}
+
+	var info JobInfo
+	testOctopus := NewOctopusWithOptions(1, queueCapacity,
+		WithMiddleware(namedMiddleware("outer", record), namedMiddleware("inner", record)),
+		WithMiddleware(func(next JobFunc) JobFunc {
+			return func(ctx context.Context) (interface{}, error) {
+				info, _ = JobInfoFromContext(ctx)
+				return next(ctx)
+			}
+		}),
+	)
+
+	assert.NoError(t, testOctopus.HandleJobPriority(func() { record("job") }, 3, "job 1"))
+	testOctopus.Wait()
+
+	assert.Equal(t, []string{"outer before", "inner before", "job", "inner after", "outer after"}, events)
+	assert.Equal(t, "job 1", info.Name)
+	assert.Equal(t, 3, info.Priority)
+	assert.Equal(t, 1, info.Attempts)
+
+	_, ok := JobInfoFromContext(context.Background())
+	assert.False(t, ok)
+}
+
+// Test for checking that retried jobs are wrapped once per execution.
+func TestMiddlewareRetry(t *testing.T) {
+	executions := 0
+	testOctopus := NewOctopusWithOptions(1, queueCapacity,
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 3}),
+		WithMiddleware(func(next JobFunc) JobFunc {
+			return func(ctx context.Context) (interface{}, error) {
+				executions++
+				return next(ctx)
+			}
+		}),
+	)
+
+	calls := 0
+	handle, err := testOctopus.Submit(func() (interface{}, error) {
+		calls++
+		return nil, errors.New("octopus down")
+	})
+	assert.NoError(t, err)
+	testOctopus.Wait()
+
+	assert.Error(t, handle.Err())
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, 3, executions)
+}
+
+// Test for checking that the timeout middleware fails jobs which outlive their timeout.
+func TestTimeoutMiddleware(t *testing.T) {
+	testOctopus := NewOctopusWithOptions(1, queueCapacity, WithMiddleware(TimeoutMiddleware(10*time.Millisecond)))
+
+	slow, err := testOctopus.SubmitContext(context.Background(), func(ctx context.Context) (interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	assert.NoError(t, err)
+
+	fast, err := testOctopus.Submit(func() (interface{}, error) { return 42, nil })
+	assert.NoError(t, err)
+	testOctopus.Wait()
+
+	assert.Equal(t, ErrJobTimeout, slow.Err())
+	result, err := fast.Wait()
+	assert.NoError(t, err)
+	assert.Equal(t, 42, result)
+	assert.Equal(t, int64(1), testOctopus.TimedOutJobs())
+	assert.Equal(t, int64(1), testOctopus.Stats().TimedOut)
+}
+
+// Test for checking that a job timing out with both the pool's timeout and the timeout middleware is counted once.
+func TestTimeoutMiddlewareJobTimeout(t *testing.T) {
+	testOctopus := NewOctopusWithOptions(1, queueCapacity,
+		WithJobTimeout(10*time.Millisecond),
+		WithMiddleware(TimeoutMiddleware(10*time.Millisecond)),
+	)
+
+	handle, err := testOctopus.SubmitContext(context.Background(), func(ctx context.Context) (interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	assert.NoError(t, err)
+	testOctopus.Wait()
+
+	assert.Equal(t, ErrJobTimeout, handle.Err())
+	assert.Equal(t, int64(1), testOctopus.TimedOutJobs())
+}
+
+// Test for checking that the retry middleware executes failed jobs again on the same worker.
+func TestRetryMiddleware(t *testing.T) {
+	retryable := errors.New("retryable")
+	testOctopus := NewOctopusWithOptions(1, queueCapacity, WithMiddleware(RetryMiddleware(RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		Retryable:      func(err error) bool { return errors.Is(err, retryable) },
+	})))
+
+	calls := 0
+	eventually, err := testOctopus.Submit(func() (interface{}, error) {
+		calls++
+		if calls < 3 {
+			return nil, retryable
+		}
+		return "done", nil
+	})
+	assert.NoError(t, err)
+
+	permanent := errors.New("permanent")
+	failing, err := testOctopus.Submit(func() (interface{}, error) { return nil, permanent })
+	assert.NoError(t, err)
+	testOctopus.Wait()
+
+	result, err := eventually.Wait()
+	assert.NoError(t, err)
+	assert.Equal(t, "done", result)
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, permanent, failing.Err())
+}
+
+// Test for checking that the retry middleware stops waiting once the job's context is done.
+func TestRetryMiddlewareCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	retry := RetryMiddleware(RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Hour})
+
+	calls := 0
+	job := retry(func(ctx context.Context) (interface{}, error) {
+		calls++
+		cancel()
+		return nil, errors.New("octopus down")
+	})
+
+	_, err := job(ctx)
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+// Test for checking the messages of the logging middleware.
+func TestLoggingMiddleware(t *testing.T) {
+	logger := &recordingLogger{}
+	testOctopus := NewOctopusWithOptions(1, queueCapacity, WithMiddleware(LoggingMiddleware(logger)))
+
+	assert.NoError(t, testOctopus.HandleJob(func() {}, "fine"))
+	_, err := testOctopus.Submit(func() (interface{}, error) { return nil, errors.New("octopus down") }, "broken")
+	assert.NoError(t, err)
+	testOctopus.Wait()
+
+	assert.Len(t, logger.messages, 4)
+	assert.Contains(t, logger.messages[0], "DEBUG job started [job fine attempt 1]")
+	assert.Contains(t, logger.messages[1], "INFO job finished [job fine attempt 1 duration")
+	assert.Contains(t, logger.messages[2], "DEBUG job started [job broken attempt 1]")
+	assert.Contains(t, logger.messages[3], "WARN job failed [job broken attempt 1 duration")
+	assert.Contains(t, logger.messages[3], "error octopus down]")
+}
@@ -0,0 +1,130 @@
+// Copyright 2021 Aadhav Vignesh
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octopool
+
+import "sync/atomic"
+
+// MPMCQueue is a struct for representing a bounded, lock-free FIFO queue which holds pending jobs.
+// It is safe for concurrent use by multiple producers and consumers.
+//
+// The implementation follows Dmitry Vyukov's bounded MPMC queue: every slot carries
+// a sequence number which tells producers and consumers whose turn it is, so that
+// claiming a slot only needs a compare-and-swap on the head or the tail.
+type MPMCQueue struct {
+	slots  []mpmcSlot    // ring buffer of slots
+	head   atomic.Uint64 // position of the next job to remove
+	tail   atomic.Uint64 // position of the next job to add
+	closed atomic.Bool   // represents if the queue accepts new jobs
+}
+
+// mpmcSlot is a struct for representing a slot of the MPMC queue.
+type mpmcSlot struct {
+	sequence atomic.Uint64 // position for which the slot is ready
+	job      Job           // job held by the slot
+}
+
+// NewMPMCQueue returns a lock-free queue with the specified capacity.
+func NewMPMCQueue(queueCapacity int) *MPMCQueue {
+	if queueCapacity < 0 {
+		queueCapacity = 0
+	}
+
+	mpmcQueue := MPMCQueue{slots: make([]mpmcSlot, queueCapacity)}
+	for i := range mpmcQueue.slots {
+		mpmcQueue.slots[i].sequence.Store(uint64(i))
+	}
+	return &mpmcQueue
+}
+
+// Push adds a job to the queue.
+func (mpmcQueue *MPMCQueue) Push(job Job) error {
+	if mpmcQueue.closed.Load() {
+		return ErrQueueClosed
+	}
+
+	size := uint64(len(mpmcQueue.slots))
+	if size == 0 {
+		return ErrQueueFull
+	}
+
+	for {
+		tail := mpmcQueue.tail.Load()
+		slot := &mpmcQueue.slots[tail%size]
+		sequence := slot.sequence.Load()
+
+		switch {
+		case sequence == tail:
+			// the slot is free, claim it by moving the tail
+			if mpmcQueue.tail.CompareAndSwap(tail, tail+1) {
+				slot.job = job
+				slot.sequence.Store(tail + 1)
+				return nil
+			}
+		case sequence < tail:
+			// the slot still holds a job from the previous lap
+			return ErrQueueFull
+		}
+		// else another producer claimed the slot, retry with the new tail
+	}
+}
+
+// Pop removes the oldest job from the queue.
+func (mpmcQueue *MPMCQueue) Pop() (Job, error) {
+	size := uint64(len(mpmcQueue.slots))
+	if size == 0 {
+		return Job{}, ErrQueueEmpty
+	}
+
+	for {
+		head := mpmcQueue.head.Load()
+		slot := &mpmcQueue.slots[head%size]
+		sequence := slot.sequence.Load()
+
+		switch {
+		case sequence == head+1:
+			// the slot holds a job, claim it by moving the head
+			if mpmcQueue.head.CompareAndSwap(head, head+1) {
+				job := slot.job
+				slot.job = Job{}
+				slot.sequence.Store(head + size)
+				return job, nil
+			}
+		case sequence < head+1:
+			// the slot has not been filled yet
+			return Job{}, ErrQueueEmpty
+		}
+		// else another consumer claimed the slot, retry with the new head
+	}
+}
+
+// Len returns the number of jobs in the queue, which may be stale under concurrent use.
+func (mpmcQueue *MPMCQueue) Len() int {
+	head := mpmcQueue.head.Load()
+	tail := mpmcQueue.tail.Load()
+	if tail < head {
+		return 0
+	}
+	return int(tail - head)
+}
+
+// Capacity returns the queue's capacity.
+func (mpmcQueue *MPMCQueue) Capacity() int {
+	return len(mpmcQueue.slots)
+}
+
+// Close stops the queue from accepting new jobs.
+func (mpmcQueue *MPMCQueue) Close() {
+	mpmcQueue.closed.Store(true)
+}
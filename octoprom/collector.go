@@ -0,0 +1,137 @@
+// Copyright 2021 Aadhav Vignesh
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package octoprom exposes the metrics of an octopus as a Prometheus collector.
+package octoprom
+
+import (
+	"github.com/burntcarrot/octopool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// defaultNamespace is the namespace of the metrics when none is provided.
+const defaultNamespace = "octopool"
+
+// Option is a function for configuring a collector.
+type Option func(c *Collector)
+
+// WithNamespace sets the namespace of the metrics, the default is "octopool".
+func WithNamespace(namespace string) Option {
+	return func(c *Collector) {
+		c.namespace = namespace
+	}
+}
+
+// WithConstLabels sets labels added to every metric, for telling several octopuses apart.
+func WithConstLabels(labels prometheus.Labels) Option {
+	return func(c *Collector) {
+		c.constLabels = labels
+	}
+}
+
+// Collector is a struct for representing a Prometheus collector reading the metrics of an octopus on every scrape.
+type Collector struct {
+	octopus     *octopool.Octopus // octopus whose metrics are collected
+	namespace   string            // namespace of the metrics
+	constLabels prometheus.Labels // labels added to every metric
+
+	submitted     *prometheus.Desc // jobs accepted by the octopus
+	completed     *prometheus.Desc // jobs which succeeded
+	failed        *prometheus.Desc // jobs which failed with an error
+	panicked      *prometheus.Desc // jobs which panicked
+	dropped       *prometheus.Desc // jobs dropped by the overflow policy
+	cancelled     *prometheus.Desc // jobs skipped or unscheduled
+	discarded     *prometheus.Desc // jobs discarded without running
+	queueDepth    *prometheus.Desc // jobs in the job queue
+	activeWorkers *prometheus.Desc // active workers
+	poolCapacity  *prometheus.Desc // workers the pool can accommodate
+	queueWait     *prometheus.Desc // time between accepting a job and its first execution
+	execution     *prometheus.Desc // time spent executing jobs
+}
+
+// NewCollector returns a collector for the octopus, configured by the options provided.
+func NewCollector(octo *octopool.Octopus, opts ...Option) *Collector {
+	c := &Collector{
+		octopus:   octo,
+		namespace: defaultNamespace,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	c.submitted = c.desc("jobs_submitted_total", "Number of jobs accepted by the octopus.")
+	c.completed = c.desc("jobs_completed_total", "Number of jobs which succeeded.")
+	c.failed = c.desc("jobs_failed_total", "Number of jobs which failed with an error, after their retries.")
+	c.panicked = c.desc("jobs_panicked_total", "Number of jobs which panicked.")
+	c.dropped = c.desc("jobs_dropped_total", "Number of jobs dropped by the overflow policy.")
+	c.cancelled = c.desc("jobs_cancelled_total", "Number of jobs skipped as their context was done or the pool failed fast, or unscheduled.")
+	c.discarded = c.desc("jobs_discarded_total", "Number of jobs discarded without running when the pool was closed or shut down.")
+	c.queueDepth = c.desc("queue_depth", "Number of jobs in the job queue.")
+	c.activeWorkers = c.desc("active_workers", "Number of active workers.")
+	c.poolCapacity = c.desc("pool_capacity", "Number of workers the pool can accommodate.")
+	c.queueWait = c.desc("job_wait_seconds", "Time between accepting a job and its first execution.")
+	c.execution = c.desc("job_duration_seconds", "Time spent executing jobs, for every attempt.")
+
+	return c
+}
+
+// Returns the description of a metric of the collector.
+func (c *Collector) desc(name, help string) *prometheus.Desc {
+	return prometheus.NewDesc(prometheus.BuildFQName(c.namespace, "", name), help, nil, c.constLabels)
+}
+
+// Describe sends the descriptions of the metrics to the channel.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.submitted
+	ch <- c.completed
+	ch <- c.failed
+	ch <- c.panicked
+	ch <- c.dropped
+	ch <- c.cancelled
+	ch <- c.discarded
+	ch <- c.queueDepth
+	ch <- c.activeWorkers
+	ch <- c.poolCapacity
+	ch <- c.queueWait
+	ch <- c.execution
+}
+
+// Collect sends a snapshot of the octopus' metrics to the channel.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	metrics := c.octopus.Metrics()
+
+	ch <- prometheus.MustNewConstMetric(c.submitted, prometheus.CounterValue, float64(metrics.Submitted))
+	ch <- prometheus.MustNewConstMetric(c.completed, prometheus.CounterValue, float64(metrics.Completed))
+	ch <- prometheus.MustNewConstMetric(c.failed, prometheus.CounterValue, float64(metrics.Failed))
+	ch <- prometheus.MustNewConstMetric(c.panicked, prometheus.CounterValue, float64(metrics.Panicked))
+	ch <- prometheus.MustNewConstMetric(c.dropped, prometheus.CounterValue, float64(metrics.Dropped))
+	ch <- prometheus.MustNewConstMetric(c.cancelled, prometheus.CounterValue, float64(metrics.Cancelled))
+	ch <- prometheus.MustNewConstMetric(c.discarded, prometheus.CounterValue, float64(metrics.Discarded))
+	ch <- prometheus.MustNewConstMetric(c.queueDepth, prometheus.GaugeValue, float64(metrics.QueueDepth))
+	ch <- prometheus.MustNewConstMetric(c.activeWorkers, prometheus.GaugeValue, float64(metrics.ActiveWorkers))
+	ch <- prometheus.MustNewConstMetric(c.poolCapacity, prometheus.GaugeValue, float64(metrics.PoolCapacity))
+	ch <- histogram(c.queueWait, metrics.QueueWait)
+	ch <- histogram(c.execution, metrics.Execution)
+}
+
+// Returns a histogram metric with the snapshot's cumulative bucket counts.
+func histogram(desc *prometheus.Desc, snapshot octopool.HistogramSnapshot) prometheus.Metric {
+	buckets := make(map[float64]uint64, len(snapshot.Buckets))
+	for i, bound := range snapshot.Buckets {
+		buckets[bound] = snapshot.Counts[i]
+	}
+
+	return prometheus.MustNewConstHistogram(desc, snapshot.Count, snapshot.Sum, buckets)
+}
@@ -0,0 +1,89 @@
+// Copyright 2021 Aadhav Vignesh
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octoprom
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/burntcarrot/octopool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/stretchr/testify/assert"
+)
+
+// Returns the text exposition of the registry, served by an httptest server.
+func scrape(t *testing.T, registry *prometheus.Registry) string {
+	server := httptest.NewServer(promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Got error while scraping: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("Got error while reading the exposition: %v", err)
+	}
+
+	return string(body)
+}
+
+// Test for checking that the collector exposes the octopus' metrics in the text exposition format.
+func TestCollector(t *testing.T) {
+	testOctopus := octopool.NewOctopusWithOptions(2, 10, octopool.WithMetricsBuckets([]float64{60}))
+	defer testOctopus.Close()
+
+	assert.NoError(t, testOctopus.HandleJob(func() {}, "completed"))
+	assert.NoError(t, testOctopus.HandleJob(func() { panic("octopus down") }, "panicked"))
+	testOctopus.Wait()
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(NewCollector(testOctopus, WithConstLabels(prometheus.Labels{"pool": "test"})))
+
+	exposition := scrape(t, registry)
+	assert.Contains(t, exposition, "# TYPE octopool_jobs_submitted_total counter")
+	assert.Contains(t, exposition, `octopool_jobs_submitted_total{pool="test"} 2`)
+	assert.Contains(t, exposition, `octopool_jobs_completed_total{pool="test"} 1`)
+	assert.Contains(t, exposition, `octopool_jobs_failed_total{pool="test"} 0`)
+	assert.Contains(t, exposition, `octopool_jobs_panicked_total{pool="test"} 1`)
+	assert.Contains(t, exposition, `octopool_jobs_dropped_total{pool="test"} 0`)
+	assert.Contains(t, exposition, `octopool_jobs_cancelled_total{pool="test"} 0`)
+	assert.Contains(t, exposition, `octopool_jobs_discarded_total{pool="test"} 0`)
+	assert.Contains(t, exposition, "# TYPE octopool_queue_depth gauge")
+	assert.Contains(t, exposition, `octopool_queue_depth{pool="test"} 0`)
+	assert.Contains(t, exposition, `octopool_pool_capacity{pool="test"} 2`)
+	assert.Contains(t, exposition, "# TYPE octopool_job_duration_seconds histogram")
+	assert.Contains(t, exposition, `octopool_job_duration_seconds_bucket{pool="test",le="60"} 2`)
+	assert.Contains(t, exposition, `octopool_job_duration_seconds_bucket{pool="test",le="+Inf"} 2`)
+	assert.Contains(t, exposition, `octopool_job_wait_seconds_count{pool="test"} 2`)
+}
+
+// Test for checking that the namespace of the metrics can be changed.
+func TestCollectorNamespace(t *testing.T) {
+	testOctopus := octopool.NewOctopus(1)
+	defer testOctopus.Close()
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(NewCollector(testOctopus, WithNamespace("workers")))
+
+	exposition := scrape(t, registry)
+	assert.Contains(t, exposition, "workers_active_workers 0")
+	assert.NotContains(t, exposition, "octopool_")
+}
@@ -15,8 +15,11 @@
 package octopool
 
 import (
+	"context"
 	"errors"
-	"log"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // pre-defined pool capacity
@@ -30,6 +33,27 @@ const defaultQueueCapacity = 10000
 // ErrNilFunction is the error raised when a function is invalid.
 var ErrNilFunction = errors.New("invalid function")
 
+// ErrNilContext is the error raised when a nil context is provided.
+var ErrNilContext = errors.New("invalid context: context must not be nil")
+
+// ErrJobPanicked matches the *PanicError delivered to a job's handle when the job panics, using errors.Is.
+var ErrJobPanicked = errors.New("job panicked")
+
+// ErrJobDropped is the error delivered to a job's handle when the job is dropped by the overflow policy.
+var ErrJobDropped = errors.New("job dropped from full job queue")
+
+// ErrJobDiscarded is the error delivered to a job's handle when the job is discarded by ShutdownNow.
+var ErrJobDiscarded = errors.New("job discarded by shutdown")
+
+// ErrJobCancelled is the error delivered to a job's handle when the job is cancelled because an earlier job failed.
+var ErrJobCancelled = errors.New("job cancelled after an earlier job failed")
+
+// ErrJobTimeout is the error delivered to a job's handle when the job outlives its timeout.
+var ErrJobTimeout = errors.New("job timed out")
+
+// ErrJobUnscheduled is the error delivered to a scheduled job's handle when the job is cancelled before it is due.
+var ErrJobUnscheduled = errors.New("scheduled job cancelled before it was due")
+
 // ErrInvalidPoolCapacity is the error raised when the pool capacity provided is invalid in nature.
 var ErrInvalidPoolCapacity = errors.New("invalid pool capacity: pool capacity must be a positive number, cannot process jobs in a pool with a capacity equal to or less than zero")
 
@@ -38,9 +62,36 @@ var ErrInvalidPoolState = errors.New("cannot assign job to closed pool")
 
 // Octopus is a struct for representing the octopus which handles the execution of jobs.
 type Octopus struct {
-	workerPool   *pool     // worker pool
-	jobQueue     *JobQueue // job queue for holding tasks
-	poolCapacity int       // pool capacity
+	workerPool       *pool                 // worker pool
+	jobQueue         Queue                 // job queue for holding tasks
+	poolCapacity     int                   // pool capacity
+	overflowPolicy   OverflowPolicy        // policy used when the job queue is full
+	onDrop           func(job Job)         // called for every job dropped by the overflow policy
+	spaceMu          sync.Mutex            // mutex for locking the space channel
+	space            chan struct{}         // closed when a worker or a queue slot may have been freed
+	failFast         bool                  // cancels the remaining jobs on the first error
+	panicPolicy      PanicPolicy           // policy used after a job panics
+	retryPolicy      *RetryPolicy          // retry policy for jobs which do not have their own
+	retries          retryTimers           // failed jobs waiting for their backoff
+	onPanic          func(err *PanicError) // called for every job which panics
+	deadLetters      *deadLetterQueue      // jobs which failed permanently, nil if disabled
+	jobTimeout       time.Duration         // timeout for jobs which do not have their own, zero means none
+	abandonOnTimeout bool                  // frees the worker of a job which outlives its timeout
+	timedOutJobs     atomic.Int64          // number of jobs which outlived their timeout
+	scheduled        scheduler             // jobs which are not due yet
+	clock            Clock                 // source of time for scheduling jobs
+	autoscaler       *autoscaler           // scales the pool capacity, nil if disabled
+	limiter          *adaptiveLimiter      // adapts the number of running jobs, nil if disabled
+	logger           Logger                // receives levelled, structured messages
+	metrics          *metrics              // counters and histograms of the jobs
+	tracer           Tracer                // traces the lifecycle of jobs, nil if disabled
+	middleware       []Middleware          // wraps the execution of every job
+	hooks            []Hooks               // called at every step of the lifecycle of jobs
+
+	errMu      sync.Mutex         // mutex for locking the collected errors
+	jobErrors  []error            // errors of the jobs which failed since the last WaitErr
+	failCtx    context.Context    // cancelled on the first error when failing fast
+	failCancel context.CancelFunc // cancels failCtx
 }
 
 // Basic helper functions:
@@ -57,12 +108,17 @@ func (octo *Octopus) ActiveWorkers() int {
 
 // AvailableWorkers returns number of available workers.
 func (octo *Octopus) AvailableWorkers() int {
-	return octo.workerPool.getPoolCapacity() - octo.workerPool.getActiveWorkersCount()
+	return octo.workerPool.getAvailableWorkersCount()
 }
 
-// Close closes the worker pool.
+// Close closes the worker pool, scheduled jobs which are not due yet are discarded,
+// and jobs waiting for a retry fail with their last error.
 func (octo *Octopus) Close() {
+	octo.discardScheduledJobs()
 	octo.workerPool.close()
+	octo.failRetries()
+	octo.jobQueue.Close()
+	octo.stopAutoscaler()
 }
 
 // Octopus related functions:
@@ -73,9 +129,6 @@ func NewOctopus(capacity int, queueCapacity ...int) *Octopus {
 	if capacity <= 0 {
 		// Suppress panic by using a default pool capacity
 		// panic(ErrInvalidPoolCapacity)
-
-		log.Println(ErrInvalidPoolCapacity)
-		log.Printf("using defaultPoolCapacity = %d as the pool capacity due to invalid pool capacity provided.", defaultPoolCapacity)
 		capacity = defaultPoolCapacity
 	}
 
@@ -83,15 +136,20 @@ func NewOctopus(capacity int, queueCapacity ...int) *Octopus {
 
 	// create an octopus with the capacity
 	if queueCapacity == nil {
-		log.Printf("using defaultQueueCapacity = %d as the queue capacity due to invalid queue capacity provided.", defaultQueueCapacity)
 		octopus = &Octopus{
 			jobQueue:     NewJobQueue(defaultQueueCapacity),
 			poolCapacity: capacity,
+			clock:        realClock{},
+			logger:       nopLogger{},
+			metrics:      newMetrics(DefaultBuckets),
 		}
 	} else {
 		octopus = &Octopus{
 			jobQueue:     NewJobQueue(queueCapacity[0]),
 			poolCapacity: capacity,
+			clock:        realClock{},
+			logger:       nopLogger{},
+			metrics:      newMetrics(DefaultBuckets),
 		}
 	}
 
@@ -103,10 +161,50 @@ func NewOctopus(capacity int, queueCapacity ...int) *Octopus {
 	return octopus
 }
 
+// NewOctopusWithOptions creates an octopus with the capacities specified, configured by the options provided.
+func NewOctopusWithOptions(capacity int, queueCapacity int, opts ...Option) *Octopus {
+	octopus := NewOctopus(capacity, queueCapacity)
+
+	for _, opt := range opts {
+		opt(octopus)
+	}
+
+	// the logger may be set by any option, report the capacity replaced by its default once it is known
+	if capacity <= 0 {
+		octopus.logger.Warn("invalid pool capacity, using the default pool capacity", "capacity", capacity, "default", defaultPoolCapacity)
+	}
+
+	// the autoscaler uses the clock, which may be set by any option
+	if octopus.autoscaler != nil {
+		octopus.startAutoscaler()
+	}
+
+	return octopus
+}
+
 // HandleJob assigns a job to a worker if workers are available, else, adds to the job queue.
 func (octo *Octopus) HandleJob(fun func(), name ...string) error {
 	// throw error if pool is closed
-	if octo.workerPool.status == PoolClosed {
+	if octo.workerPool.isClosed() {
+		return ErrInvalidPoolState
+	}
+
+	// throw error if function provided is invalid
+	if fun == nil {
+		return ErrNilFunction
+	}
+
+	// create a job
+	job := Job{function: fun, name: jobName(name)}
+
+	return octo.dispatch(job)
+}
+
+// HandleJobPriority assigns a job to a worker if workers are available, else, adds to the job queue with the priority provided.
+// Priorities only affect the order of jobs in a priority job queue, higher priorities run first.
+func (octo *Octopus) HandleJobPriority(fun func(), priority int, name ...string) error {
+	// throw error if pool is closed
+	if octo.workerPool.isClosed() {
 		return ErrInvalidPoolState
 	}
 
@@ -116,40 +214,427 @@ func (octo *Octopus) HandleJob(fun func(), name ...string) error {
 	}
 
 	// create a job
-	job := Job{function: fun, name: name[0]}
+	job := Job{function: fun, name: jobName(name), priority: priority}
+
+	return octo.dispatch(job)
+}
+
+// HandleJobContext assigns a context-aware job to a worker if workers are available, else, adds to the job queue.
+// The job receives ctx when it runs, and is skipped if ctx is done before it leaves the job queue.
+func (octo *Octopus) HandleJobContext(ctx context.Context, fun func(ctx context.Context) error, name ...string) error {
+	// throw error if pool is closed
+	if octo.workerPool.isClosed() {
+		return ErrInvalidPoolState
+	}
+
+	// throw error if function or context provided is invalid
+	if fun == nil {
+		return ErrNilFunction
+	}
+	if ctx == nil {
+		return ErrNilContext
+	}
+
+	// do not accept jobs whose context is already done
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// create a job
+	job := Job{
+		ctxFunction: func(ctx context.Context) (interface{}, error) {
+			return nil, fun(ctx)
+		},
+		ctx:  ctx,
+		name: jobName(name),
+	}
+
+	return octo.dispatch(job)
+}
+
+// Submit handles a job which returns a result, and returns a handle for collecting the job's outcome.
+func (octo *Octopus) Submit(fun func() (interface{}, error), name ...string) (*JobHandle, error) {
+	// throw error if function provided is invalid
+	if fun == nil {
+		return nil, ErrNilFunction
+	}
+
+	return octo.SubmitContext(context.Background(), func(ctx context.Context) (interface{}, error) {
+		return fun()
+	}, name...)
+}
+
+// SubmitContext handles a context-aware job which returns a result, and returns a handle for collecting the job's outcome.
+// If ctx is done while the job is still waiting in the job queue, the job is skipped and the handle receives ctx's error.
+func (octo *Octopus) SubmitContext(ctx context.Context, fun func(ctx context.Context) (interface{}, error), name ...string) (*JobHandle, error) {
+	// throw error if pool is closed
+	if octo.workerPool.isClosed() {
+		return nil, ErrInvalidPoolState
+	}
+
+	// throw error if function or context provided is invalid
+	if fun == nil {
+		return nil, ErrNilFunction
+	}
+	if ctx == nil {
+		return nil, ErrNilContext
+	}
 
-	// check if workers are available and assign a job, else add the job to the queue
-	if octo.workerPool.isWorkerAvailable() {
-		log.Println("assigning job:", job.name, "to a worker.")
+	// do not accept jobs whose context is already done
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// create a job with a handle
+	job := Job{ctxFunction: fun, ctx: ctx, name: jobName(name)}
+	job.handle = newJobHandle(job.name)
+
+	if err := octo.dispatch(job); err != nil {
+		return nil, err
+	}
+
+	return job.handle, nil
+}
+
+// Assigns the job to a worker if workers are available, else, adds it to the job queue.
+// The overflow policy decides what happens to the job when the job queue is full.
+func (octo *Octopus) dispatch(job Job) (err error) {
+	job.submittedAt = octo.clock.Now()
+	octo.traceSubmit(&job)
+	octo.hookSubmit(job)
+	defer func() {
+		if err != nil {
+			job.traceEnd(err)
+		}
+	}()
+
+	for {
+		// take the space channel before trying the pool, so that no signal is missed
+		space := octo.waitForSpace()
+
+		err := octo.offer(job)
+		if !errors.Is(err, ErrQueueFull) {
+			return err
+		}
+
+		switch octo.overflowPolicy {
+		case OverflowDropOldest:
+			// a queue without capacity never holds an older job, drop the job itself
+			if octo.jobQueue.Capacity() == 0 {
+				octo.metrics.accepted(job)
+				octo.dropJob(job)
+				return nil
+			}
+			oldest, err := octo.evict()
+			if err != nil {
+				// workers emptied the queue since the job was offered, offer it again
+				continue
+			}
+			octo.dropJob(oldest)
+			octo.workerPool.donePendingJob()
+		case OverflowDropNewest:
+			// the job counts as submitted like the ones dropped by OverflowDropOldest
+			octo.metrics.accepted(job)
+			octo.dropJob(job)
+			return nil
+		case OverflowCallerRuns:
+			octo.runInCaller(job)
+			return nil
+		case OverflowBlock:
+			if err := octo.blockOn(job.ctx, space); err != nil {
+				return err
+			}
+		default:
+			return ErrQueueFull
+		}
+	}
+}
+
+// Assigns the job to a worker if workers are available, else, adds it to the job queue.
+// Returns ErrQueueFull without blocking if the job queue is full.
+func (octo *Octopus) offer(job Job) error {
+	octo.workerPool.addPendingJob()
+
+	if err := octo.place(job); err != nil {
+		octo.workerPool.donePendingJob()
+		return err
+	}
+	octo.metrics.accepted(job)
+	job.traceEnqueued()
+	return nil
+}
+
+// Assigns a pending job to a worker if workers are available, else, adds it to the job queue.
+func (octo *Octopus) place(job Job) error {
+	if octo.workerPool.acquireWorker() {
+		if octo.debugEnabled() {
+			octo.logger.Debug("assigning job to a worker", "job", job.name)
+		}
 		octo.workerPool.assignJob(job)
-	} else {
-		log.Printf("adding job: %s to queue\n", job.name)
-		octo.jobQueue.AddJob(job)
+		return nil
 	}
 
+	if err := octo.jobQueue.Push(job); err != nil {
+		return err
+	}
+	octo.hookQueued(job)
+	octo.workerPool.stats.observeQueued(octo.jobQueue.Len())
+
+	if octo.debugEnabled() {
+		octo.logger.Debug("adding job to queue", "job", job.name, "queued", octo.jobQueue.Len())
+	}
+
+	// a worker may have been freed after the pool was checked, promote the job if so
+	octo.processNext()
 	return nil
 }
 
-// Promotes a job to the pool and assigns a worker to it.
-func (octo *Octopus) processNext() {
-	// if queue is not empty, remove job from the queue
-	if octo.jobQueue.IsNotEmpty() {
-		job, err := octo.jobQueue.RemoveJob()
-		log.Println("removing job:", job.name, "from queue and assigning to a worker.")
+// TrySubmit assigns the job to a worker if workers are available, else, adds it to the job queue.
+// It never blocks, and returns false if the job could not be accepted.
+func (octo *Octopus) TrySubmit(job Job) bool {
+	// reject if pool is closed or the job is invalid
+	if octo.workerPool.isClosed() || !job.isValid() {
+		return false
+	}
 
+	job.submittedAt = octo.clock.Now()
+	octo.traceSubmit(&job)
+	octo.hookSubmit(job)
+	if err := octo.offer(job); err != nil {
+		job.traceEnd(err)
+		return false
+	}
+	return true
+}
+
+// SubmitBlocking assigns the job to a worker if workers are available, else, adds it to the job queue.
+// It blocks until a worker or a queue slot is available, and returns ctx's error if ctx is done first.
+func (octo *Octopus) SubmitBlocking(ctx context.Context, job Job) (err error) {
+	// throw error if pool is closed
+	if octo.workerPool.isClosed() {
+		return ErrInvalidPoolState
+	}
+
+	// throw error if job or context provided is invalid
+	if !job.isValid() {
+		return ErrNilFunction
+	}
+	if ctx == nil {
+		return ErrNilContext
+	}
+
+	job.submittedAt = octo.clock.Now()
+	octo.traceSubmit(&job)
+	octo.hookSubmit(job)
+	defer func() {
 		if err != nil {
-			log.Println("error occurred while removing the job.")
-			return
+			job.traceEnd(err)
+		}
+	}()
+
+	for {
+		// take the space channel before trying the pool, so that no signal is missed
+		space := octo.waitForSpace()
+
+		err := octo.offer(job)
+		if !errors.Is(err, ErrQueueFull) {
+			return err
+		}
+
+		if err := octo.blockOn(ctx, space); err != nil {
+			return err
+		}
+	}
+}
+
+// Promotes jobs to the pool and assigns workers to them, as long as workers are available.
+func (octo *Octopus) processNext() {
+	// wake up jobs blocked on a full pool
+	defer octo.signalSpace()
+
+	for octo.workerPool.acquireWorker() {
+		job, ok := octo.takeNext()
+		if !ok {
+			octo.workerPool.releaseWorker()
+
+			// a job may have been added while the worker was reserved, in which case
+			// its submitter could not reserve a worker and relies on this check
+			if octo.jobQueue.Len() == 0 {
+				return
+			}
+			continue
 		}
 
 		// assign the job to the worker
+		if octo.debugEnabled() {
+			octo.logger.Debug("assigning queued job to a worker", "job", job.name, "queued", octo.jobQueue.Len())
+		}
 		octo.workerPool.assignJob(job)
-		log.Println("assigned job:", job.name, "to a worker.")
 	}
 }
 
+// Removes the next job which can still be run from the queue, returns false if there is none.
+func (octo *Octopus) takeNext() (Job, bool) {
+	for {
+		job, err := octo.jobQueue.Pop()
+		if err != nil {
+			if !errors.Is(err, ErrQueueEmpty) {
+				octo.logger.Error("cannot remove job from queue", "error", err)
+			}
+			return Job{}, false
+		}
+
+		// wake up jobs blocked on a full job queue
+		octo.signalSpace()
+
+		// skip jobs which were cancelled while waiting in the queue
+		if err := octo.skipReason(job); err != nil {
+			octo.skipJob(job, err)
+			octo.workerPool.donePendingJob()
+			continue
+		}
+
+		return job, true
+	}
+}
+
+// Executes the job, recovering from panics and collecting the job's error.
+// Returns false if the job failed and was scheduled for a retry, in which case it is still pending.
+func (octo *Octopus) runJob(job Job) (finished bool) {
+	// the job may have been cancelled after it was assigned
+	if err := octo.skipReason(job); err != nil {
+		octo.skipJob(job, err)
+		return true
+	}
+
+	// retries run with the context the job was submitted with
+	submitted := job.ctx
+	ctx, cancel := octo.runContext(job.ctx)
+	defer cancel()
+
+	timeout := octo.timeoutFor(job)
+	if timeout > 0 {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = withTimeout(ctx, timeout)
+		defer cancelTimeout()
+	}
+	job.ctx = job.traceStart(ctx)
+	job.attempts++
+	if job.attempts == 1 {
+		wait := octo.clock.Now().Sub(job.submittedAt)
+		octo.metrics.queueWait.observe(wait)
+		octo.workerPool.stats.observeWait(job.name, wait)
+	}
+
+	var result interface{}
+	var err error
+	start := time.Now()
+	defer func() {
+		finished = true
+
+		// recover from the panic, the panic policy decides what happens next
+		var panicErr *PanicError
+		if r := recover(); r != nil {
+			panicErr = octo.handlePanic(job, r)
+			err = panicErr
+		}
+
+		// count the timeouts of the pool and of TimeoutMiddleware alike, once per job however many attempts time out
+		if errors.Is(err, ErrJobTimeout) && !job.timedOut {
+			job.timedOut = true
+			octo.timedOutJobs.Add(1)
+		}
+
+		// adapt the concurrency limit to the job's latency and error
+		elapsed := time.Since(start)
+		octo.metrics.execution.observe(elapsed)
+		octo.workerPool.stats.observeRun(job.name, elapsed)
+		octo.observeJob(elapsed, err)
+		job.traceEnd(err)
+		octo.hookFinish(job, err)
+
+		if panicErr == nil {
+			if err != nil && octo.scheduleRetry(submitted, job, err) {
+				finished = false
+				return
+			}
+			octo.metrics.finished(err)
+			job.handle.finish(result, err)
+		}
+
+		if err != nil {
+			octo.recordError(job, err)
+
+			job.ctx = submitted
+			octo.deadLetter(job, err)
+		}
+
+		if panicErr != nil {
+			octo.applyPanicPolicy(panicErr)
+		}
+	}()
+
+	octo.hookStart(job)
+	result, err = octo.execute(octo.applyMiddleware(job), timeout)
+	return
+}
+
+// Returns the reason for skipping the job, or nil if the job can run.
+func (octo *Octopus) skipReason(job Job) error {
+	if job.isCancelled() {
+		return job.ctx.Err()
+	}
+	if octo.hasFailed() {
+		return ErrJobCancelled
+	}
+	return nil
+}
+
+// Skips the job and delivers the reason to the job's handle.
+func (octo *Octopus) skipJob(job Job, reason error) {
+	if octo.debugEnabled() {
+		octo.logger.Debug("skipping job", "job", job.name, "reason", reason)
+	}
+	octo.metrics.cancelled.Add(1)
+	job.traceEnd(reason)
+	job.handle.finish(nil, reason)
+}
+
+// Returns the job name from the optional name arguments.
+func jobName(name []string) string {
+	if len(name) == 0 {
+		return ""
+	}
+	return name[0]
+}
+
 // Waits on workers to finish the job
 func (octo *Octopus) Wait() {
-	log.Println("Waiting for jobs to finish....")
-	octo.workerPool.wg.Wait()
+	if octo.debugEnabled() {
+		octo.logger.Debug("waiting for jobs to finish", "pending", octo.workerPool.getPendingJobsCount(), "queued", octo.jobQueue.Len())
+	}
+	octo.workerPool.wait()
+}
+
+// Resize changes the number of workers the pool can accommodate, without interrupting running jobs.
+// Growing the pool assigns queued jobs to the new workers right away. Shrinking the pool stops
+// surplus idle workers, and busy workers above the new capacity stop once their job is finished.
+func (octo *Octopus) Resize(capacity int) error {
+	// throw error if capacity provided is invalid
+	if capacity <= 0 {
+		return ErrInvalidPoolCapacity
+	}
+
+	// throw error if pool is closed
+	if octo.workerPool.isClosed() {
+		return ErrInvalidPoolState
+	}
+
+	octo.logger.Info("resizing pool", "from", octo.workerPool.getPoolCapacity(), "to", capacity,
+		"active", octo.workerPool.getActiveWorkersCount())
+	octo.workerPool.resize(capacity)
+
+	// assign queued jobs to the new workers
+	octo.processNext()
+	return nil
 }
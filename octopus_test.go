@@ -15,6 +15,9 @@
 package octopool
 
 import (
+	"context"
+	"errors"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -35,7 +38,7 @@ func TestOctopusWithQueueCapacity(t *testing.T) {
 
 	assert.NotNil(t, testOctopus, "octopus should not be nil")
 	assert.Equal(t, 20, testOctopus.workerPool.capacity, "octopus should have the capacity as mentioned (20).")
-	assert.Equal(t, 50, testOctopus.jobQueue.QueueCapacity(), "queue should have the capacity as mentioned (50).")
+	assert.Equal(t, 50, testOctopus.jobQueue.Capacity(), "queue should have the capacity as mentioned (50).")
 }
 
 // Test for checking the behavior when an octopus with an invalid capacity is created.
@@ -113,7 +116,7 @@ func TestOctopusHandleJobFullPool(t *testing.T) {
 		t.Errorf("Got error while handling job: %v", err)
 	}
 
-	assert.Equal(t, 1, testOctopus.jobQueue.totalJobs)
+	assert.Equal(t, 1, testOctopus.jobQueue.Len())
 }
 
 // Test for checking the behavior when an octopus is given an invalid job.
@@ -148,13 +151,13 @@ func TestOctopusProcessNext(t *testing.T) {
 	}
 
 	// one job should be in queue
-	assert.Equal(t, 1, testOctopus.jobQueue.totalJobs)
+	assert.Equal(t, 1, testOctopus.jobQueue.Len())
 
 	// simulate wait for job
 	time.Sleep(2 * time.Second)
 
 	// job should be promoted from queue to pool
-	assert.Equal(t, 0, testOctopus.jobQueue.totalJobs)
+	assert.Equal(t, 0, testOctopus.jobQueue.Len())
 }
 
 // Test for checking the behavior when the number of active workers when a job is assigned to an octopus.
@@ -232,3 +235,272 @@ func TestOctopusWait(t *testing.T) {
 
 	assert.Equal(t, 5, testOctopus.AvailableWorkers())
 }
+
+func TestOctopusHandleJobContext(t *testing.T) {
+	testOctopus := NewOctopus(1)
+
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "octo")
+
+	var received atomic.Value
+	job1 := func(ctx context.Context) error {
+		received.Store(ctx.Value(ctxKey{}))
+		return nil
+	}
+
+	err := testOctopus.HandleJobContext(ctx, job1, "job 1")
+	if err != nil {
+		t.Errorf("Got error while handling job: %v", err)
+	}
+
+	testOctopus.Wait()
+
+	assert.Equal(t, "octo", received.Load(), "job should receive the submitted context.")
+}
+
+func TestOctopusHandleJobContextDone(t *testing.T) {
+	testOctopus := NewOctopus(1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := testOctopus.HandleJobContext(ctx, func(ctx context.Context) error { return nil }, "job 1")
+
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.Equal(t, 0, testOctopus.ActiveWorkers())
+}
+
+func TestOctopusHandleJobContextInvalid(t *testing.T) {
+	testOctopus := NewOctopus(1)
+
+	//nolint:staticcheck // nil context is the behavior under test
+	err := testOctopus.HandleJobContext(nil, func(ctx context.Context) error { return nil }, "job 1")
+	assert.Equal(t, ErrNilContext, err)
+
+	err = testOctopus.HandleJobContext(context.Background(), nil, "job 1")
+	assert.Equal(t, ErrNilFunction, err)
+}
+
+func TestOctopusProcessNextSkipsCancelled(t *testing.T) {
+	testOctopus := NewOctopus(1)
+
+	release := make(chan struct{})
+	job1 := func() {
+		<-release
+	}
+
+	var ran int32
+	job2 := func(ctx context.Context) error {
+		atomic.StoreInt32(&ran, 1)
+		return nil
+	}
+
+	err := testOctopus.HandleJob(job1, "job 1")
+	if err != nil {
+		t.Errorf("Got error while handling job: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err = testOctopus.HandleJobContext(ctx, job2, "job 2")
+	if err != nil {
+		t.Errorf("Got error while handling job: %v", err)
+	}
+
+	// cancel the queued job before a worker becomes available
+	cancel()
+	close(release)
+
+	testOctopus.Wait()
+
+	assert.Equal(t, int32(0), atomic.LoadInt32(&ran), "cancelled job should not run.")
+	assert.Equal(t, 0, testOctopus.jobQueue.Len())
+}
+
+func TestOctopusHandleJobContextCancelRunning(t *testing.T) {
+	testOctopus := NewOctopus(1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	started := make(chan struct{})
+	job1 := func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	err := testOctopus.HandleJobContext(ctx, job1, "job 1")
+	if err != nil {
+		t.Errorf("Got error while handling job: %v", err)
+	}
+
+	<-started
+	cancel()
+
+	// the running job stops cooperatively once its context is cancelled
+	testOctopus.Wait()
+
+	assert.Equal(t, 1, testOctopus.AvailableWorkers())
+}
+
+func TestOctopusTrySubmit(t *testing.T) {
+	testOctopus := NewOctopus(1, 1)
+
+	release := make(chan struct{})
+	job1 := NewJob(func() { <-release }).WithName("job 1")
+	job2 := NewJob(func() {}).WithName("job 2")
+	job3 := NewJob(func() {}).WithName("job 3")
+
+	assert.True(t, testOctopus.TrySubmit(job1), "job should be assigned to a worker.")
+	assert.True(t, testOctopus.TrySubmit(job2), "job should be added to the queue.")
+	assert.False(t, testOctopus.TrySubmit(job3), "job should not be accepted by a full pool.")
+	assert.False(t, testOctopus.TrySubmit(NewJob(nil)), "invalid job should not be accepted.")
+
+	close(release)
+	testOctopus.Wait()
+
+	testOctopus.Close()
+	assert.False(t, testOctopus.TrySubmit(job3), "job should not be accepted by a closed pool.")
+}
+
+func TestOctopusSubmitBlocking(t *testing.T) {
+	testOctopus := NewOctopus(1, 0)
+
+	release := make(chan struct{})
+	err := testOctopus.SubmitBlocking(context.Background(), NewJob(func() { <-release }))
+	if err != nil {
+		t.Errorf("Got error while submitting job: %v", err)
+	}
+
+	submitted := make(chan error)
+	go func() {
+		submitted <- testOctopus.SubmitBlocking(context.Background(), NewJob(func() {}))
+	}()
+
+	select {
+	case <-submitted:
+		t.Fatal("job should block while the pool is full.")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+
+	assert.NoError(t, <-submitted)
+	testOctopus.Wait()
+}
+
+func TestOctopusSubmitBlockingDeadline(t *testing.T) {
+	testOctopus := NewOctopus(1, 0)
+
+	release := make(chan struct{})
+	defer close(release)
+
+	err := testOctopus.SubmitBlocking(context.Background(), NewJob(func() { <-release }))
+	if err != nil {
+		t.Errorf("Got error while submitting job: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err = testOctopus.SubmitBlocking(ctx, NewJob(func() {}))
+
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Equal(t, ErrNilFunction, testOctopus.SubmitBlocking(ctx, NewJob(nil)))
+}
+
+// Test for checking that growing the pool assigns queued jobs to the new workers right away.
+func TestOctopusResizeGrow(t *testing.T) {
+	testOctopus := NewOctopus(1, queueCapacity)
+
+	release := make(chan struct{})
+	var started int32
+	for i := 0; i < 4; i++ {
+		assert.NoError(t, testOctopus.HandleJob(func() {
+			atomic.AddInt32(&started, 1)
+			<-release
+		}))
+	}
+	assert.Equal(t, 1, testOctopus.ActiveWorkers())
+
+	assert.NoError(t, testOctopus.Resize(4))
+
+	assert.Equal(t, 4, testOctopus.PoolCapacity())
+	assert.Equal(t, 4, testOctopus.ActiveWorkers())
+	assert.Equal(t, 0, testOctopus.jobQueue.Len())
+
+	close(release)
+	testOctopus.Wait()
+	assert.Equal(t, int32(4), atomic.LoadInt32(&started))
+}
+
+// Test for checking that shrinking the pool lets running jobs finish, and limits the jobs started afterwards.
+func TestOctopusResizeShrink(t *testing.T) {
+	testOctopus := NewOctopus(4, queueCapacity)
+
+	release := make(chan struct{})
+	var running, peak int32
+	job := func() {
+		current := atomic.AddInt32(&running, 1)
+		for {
+			old := atomic.LoadInt32(&peak)
+			if current <= old || atomic.CompareAndSwapInt32(&peak, old, current) {
+				break
+			}
+		}
+		<-release
+		time.Sleep(time.Millisecond)
+		atomic.AddInt32(&running, -1)
+	}
+
+	for i := 0; i < 4; i++ {
+		assert.NoError(t, testOctopus.HandleJob(job))
+	}
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&running) == 4 }, time.Second, time.Millisecond)
+
+	assert.NoError(t, testOctopus.Resize(1))
+
+	// running jobs are not interrupted
+	assert.Equal(t, 4, testOctopus.ActiveWorkers())
+	assert.Equal(t, 0, testOctopus.AvailableWorkers())
+
+	atomic.StoreInt32(&peak, 0)
+	for i := 0; i < 4; i++ {
+		assert.NoError(t, testOctopus.HandleJob(job))
+	}
+
+	close(release)
+	testOctopus.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&peak), "queued jobs should only run one at a time.")
+	assert.Equal(t, 1, testOctopus.AvailableWorkers())
+	assert.LessOrEqual(t, testOctopus.workerPool.spawnedWorkers, 1)
+}
+
+// Test for checking that shrinking the pool stops surplus idle workers.
+func TestOctopusResizeIdleWorkers(t *testing.T) {
+	testOctopus := NewOctopus(4, queueCapacity)
+
+	release := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		assert.NoError(t, testOctopus.HandleJob(func() { <-release }))
+	}
+	close(release)
+	testOctopus.Wait()
+	assert.Equal(t, 4, testOctopus.workerPool.spawnedWorkers)
+
+	assert.NoError(t, testOctopus.Resize(2))
+
+	assert.Equal(t, 2, testOctopus.workerPool.spawnedWorkers)
+	assert.Equal(t, 2, testOctopus.AvailableWorkers())
+}
+
+// Test for checking that invalid capacities and closed pools cannot be resized.
+func TestOctopusResizeInvalid(t *testing.T) {
+	testOctopus := NewOctopus(2, queueCapacity)
+
+	assert.ErrorIs(t, testOctopus.Resize(0), ErrInvalidPoolCapacity)
+	assert.ErrorIs(t, testOctopus.Resize(-1), ErrInvalidPoolCapacity)
+	assert.Equal(t, 2, testOctopus.PoolCapacity())
+
+	testOctopus.Close()
+	assert.ErrorIs(t, testOctopus.Resize(4), ErrInvalidPoolState)
+}
@@ -0,0 +1,199 @@
+// Copyright 2021 Aadhav Vignesh
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package octotrace traces the lifecycle of the jobs of an octopus with OpenTelemetry.
+//
+// Every job produces an enqueue span, a child of the span of the context the job was handled with,
+// followed by a queue wait span and an execution span for every attempt, both children of the enqueue span.
+package octotrace
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/burntcarrot/octopool"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName is the name of the tracer provided by the tracer provider.
+const instrumentationName = "github.com/burntcarrot/octopool/octotrace"
+
+// Names of the spans.
+const (
+	enqueueSpanName = "octopool.enqueue"
+	waitSpanName    = "octopool.wait"
+	runSpanName     = "octopool.run"
+)
+
+// Keys of the attributes of the spans.
+const (
+	NameKey    = attribute.Key("octopool.job.name")    // name of the job
+	OutcomeKey = attribute.Key("octopool.job.outcome") // outcome of the job, see Outcome
+	AttemptKey = attribute.Key("octopool.job.attempt") // attempt of an execution, starting at 1
+)
+
+// Option is a function for configuring a tracer.
+type Option func(t *Tracer)
+
+// WithTracerProvider sets the tracer provider creating the spans, the default is the global tracer provider.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(t *Tracer) {
+		t.provider = provider
+	}
+}
+
+// Tracer is a struct for representing an octopool.Tracer producing OpenTelemetry spans.
+type Tracer struct {
+	provider trace.TracerProvider // provides the tracer
+	tracer   trace.Tracer         // creates the spans
+}
+
+// NewTracer returns a tracer configured by the options provided, to be used with octopool.WithTracer.
+func NewTracer(opts ...Option) *Tracer {
+	t := &Tracer{provider: otel.GetTracerProvider()}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	t.tracer = t.provider.Tracer(instrumentationName)
+	return t
+}
+
+// Submit starts the enqueue span of a job, as a child of ctx's span.
+func (t *Tracer) Submit(ctx context.Context, name string) octopool.JobTrace {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	ctx, enqueue := t.tracer.Start(ctx, enqueueSpanName,
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(NameKey.String(name)),
+	)
+
+	return &jobTrace{
+		tracer:  t.tracer,
+		ctx:     ctx,
+		name:    name,
+		enqueue: enqueue,
+	}
+}
+
+// jobTrace is a struct for representing the spans of a job.
+type jobTrace struct {
+	tracer   trace.Tracer    // creates the spans
+	ctx      context.Context // context of the enqueue span, parent of the other spans
+	name     string          // name of the job
+	attempts int             // number of executions started
+	enqueue  trace.Span      // span from handling the job to its acceptance, nil once ended
+	wait     trace.Span      // span from the job's acceptance to its first execution, nil once ended
+	run      trace.Span      // span of the current execution, nil if the job is not running
+	mu       sync.Mutex      // mutex for locking
+}
+
+// Enqueued ends the enqueue span and starts the wait span, unless the job already started.
+func (jt *jobTrace) Enqueued() {
+	jt.mu.Lock()
+	defer jt.mu.Unlock()
+
+	if jt.enqueue == nil {
+		return
+	}
+	jt.enqueued()
+}
+
+// Ends the enqueue span and starts the wait span, the lock must be held.
+func (jt *jobTrace) enqueued() {
+	jt.enqueue.End()
+	jt.enqueue = nil
+
+	_, jt.wait = jt.tracer.Start(jt.ctx, waitSpanName, trace.WithAttributes(NameKey.String(jt.name)))
+}
+
+// Start ends the spans preceding the execution and starts an execution span, the span is added to ctx.
+func (jt *jobTrace) Start(ctx context.Context) context.Context {
+	jt.mu.Lock()
+	defer jt.mu.Unlock()
+
+	// the job may start before its acceptance is traced
+	if jt.enqueue != nil {
+		jt.enqueued()
+	}
+	if jt.wait != nil {
+		jt.wait.End()
+		jt.wait = nil
+	}
+
+	jt.attempts++
+	_, jt.run = jt.tracer.Start(jt.ctx, runSpanName,
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(NameKey.String(jt.name), AttemptKey.Int(jt.attempts)),
+	)
+
+	if ctx == nil {
+		return nil
+	}
+	return trace.ContextWithSpan(ctx, jt.run)
+}
+
+// End ends the execution span with the job's outcome, or the spans of a job which never runs.
+func (jt *jobTrace) End(err error) {
+	jt.mu.Lock()
+	defer jt.mu.Unlock()
+
+	for _, span := range []*trace.Span{&jt.run, &jt.wait, &jt.enqueue} {
+		if *span != nil {
+			endSpan(*span, err)
+			*span = nil
+		}
+	}
+}
+
+// Ends the span with the outcome of the error.
+func endSpan(span trace.Span, err error) {
+	span.SetAttributes(OutcomeKey.String(Outcome(err)))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Outcome returns the outcome of a job which ended with the error provided:
+// completed, failed, panicked, timed_out, dropped, rejected or cancelled.
+func Outcome(err error) string {
+	var panicErr *octopool.PanicError
+
+	switch {
+	case err == nil:
+		return "completed"
+	case errors.As(err, &panicErr):
+		return "panicked"
+	case errors.Is(err, octopool.ErrJobTimeout):
+		return "timed_out"
+	case errors.Is(err, octopool.ErrJobDropped):
+		return "dropped"
+	case errors.Is(err, octopool.ErrQueueFull), errors.Is(err, octopool.ErrInvalidPoolState):
+		return "rejected"
+	case errors.Is(err, octopool.ErrJobCancelled), errors.Is(err, octopool.ErrJobDiscarded),
+		errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return "cancelled"
+	default:
+		return "failed"
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright 2021 Aadhav Vignesh
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octotrace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/burntcarrot/octopool"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// Returns an in-memory exporter and a tracer exporting to it synchronously.
+func newTestTracer() (*tracetest.InMemoryExporter, *Tracer, *sdktrace.TracerProvider) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	return exporter, NewTracer(WithTracerProvider(provider)), provider
+}
+
+// Returns the ended spans with the name provided.
+func spansNamed(exporter *tracetest.InMemoryExporter, name string) tracetest.SpanStubs {
+	var spans tracetest.SpanStubs
+	for _, span := range exporter.GetSpans() {
+		if span.Name == name {
+			spans = append(spans, span)
+		}
+	}
+	return spans
+}
+
+// Returns the value of the span's attribute.
+func attributeOf(span tracetest.SpanStub, key attribute.Key) attribute.Value {
+	for _, kv := range span.Attributes {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+// Test for checking the spans of a job, and that they are linked to the submitter's span.
+func TestTracer(t *testing.T) {
+	exporter, tracer, provider := newTestTracer()
+	testOctopus := octopool.NewOctopusWithOptions(1, 10, octopool.WithTracer(tracer))
+	defer testOctopus.Close()
+
+	ctx, submitter := provider.Tracer("test").Start(context.Background(), "submitter")
+	err := testOctopus.HandleJobContext(ctx, func(ctx context.Context) error {
+		_, span := provider.Tracer("test").Start(ctx, "inside")
+		span.End()
+		return nil
+	}, "job 1")
+	assert.NoError(t, err)
+	testOctopus.Wait()
+	submitter.End()
+
+	enqueue := spansNamed(exporter, enqueueSpanName)
+	wait := spansNamed(exporter, waitSpanName)
+	run := spansNamed(exporter, runSpanName)
+	inside := spansNamed(exporter, "inside")
+	if !assert.Len(t, enqueue, 1) || !assert.Len(t, wait, 1) || !assert.Len(t, run, 1) || !assert.Len(t, inside, 1) {
+		return
+	}
+
+	assert.Equal(t, submitter.SpanContext().SpanID(), enqueue[0].Parent.SpanID())
+	assert.Equal(t, enqueue[0].SpanContext.SpanID(), wait[0].Parent.SpanID())
+	assert.Equal(t, enqueue[0].SpanContext.SpanID(), run[0].Parent.SpanID())
+	assert.Equal(t, run[0].SpanContext.SpanID(), inside[0].Parent.SpanID())
+	assert.Equal(t, submitter.SpanContext().TraceID(), run[0].SpanContext.TraceID())
+
+	assert.Equal(t, "job 1", attributeOf(run[0], NameKey).AsString())
+	assert.Equal(t, "completed", attributeOf(run[0], OutcomeKey).AsString())
+	assert.Equal(t, int64(1), attributeOf(run[0], AttemptKey).AsInt64())
+	assert.False(t, wait[0].EndTime.After(run[0].StartTime))
+}
+
+// Test for checking that every attempt of a retried job has its own execution span.
+func TestTracerRetry(t *testing.T) {
+	exporter, tracer, _ := newTestTracer()
+	testOctopus := octopool.NewOctopusWithOptions(1, 10,
+		octopool.WithTracer(tracer),
+		octopool.WithRetryPolicy(octopool.RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond}),
+	)
+	defer testOctopus.Close()
+
+	assert.True(t, testOctopus.TrySubmit(octopool.NewJobErr(func() error {
+		return errors.New("octopus down")
+	}).WithName("failing")))
+	testOctopus.Wait()
+
+	run := spansNamed(exporter, runSpanName)
+	if !assert.Len(t, run, 2) {
+		return
+	}
+	for i, span := range run {
+		assert.Equal(t, int64(i+1), attributeOf(span, AttemptKey).AsInt64())
+		assert.Equal(t, "failed", attributeOf(span, OutcomeKey).AsString())
+		assert.Equal(t, codes.Error, span.Status.Code)
+	}
+	assert.Len(t, spansNamed(exporter, waitSpanName), 1)
+}
+
+// Test for checking that a dropped job only has an enqueue span, carrying its outcome.
+func TestTracerDropped(t *testing.T) {
+	exporter, tracer, _ := newTestTracer()
+	testOctopus := octopool.NewOctopusWithOptions(1, 1,
+		octopool.WithTracer(tracer),
+		octopool.WithOverflowPolicy(octopool.OverflowDropNewest),
+	)
+	defer testOctopus.Close()
+
+	release := make(chan struct{})
+	assert.NoError(t, testOctopus.HandleJob(func() { <-release }, "blocker"))
+	assert.NoError(t, testOctopus.HandleJob(func() {}, "queued"))
+	assert.NoError(t, testOctopus.HandleJob(func() {}, "dropped"))
+	close(release)
+	testOctopus.Wait()
+
+	outcomes := make(map[string]string)
+	for _, span := range spansNamed(exporter, enqueueSpanName) {
+		outcomes[attributeOf(span, NameKey).AsString()] = attributeOf(span, OutcomeKey).AsString()
+	}
+	assert.Equal(t, map[string]string{"blocker": "", "queued": "", "dropped": "dropped"}, outcomes)
+	assert.Len(t, spansNamed(exporter, runSpanName), 2)
+}
+
+// Test for checking the outcomes of job errors.
+func TestOutcome(t *testing.T) {
+	tests := map[error]string{
+		errors.New("octopus down"):   "failed",
+		&octopool.PanicError{}:       "panicked",
+		octopool.ErrJobTimeout:       "timed_out",
+		octopool.ErrJobDropped:       "dropped",
+		octopool.ErrQueueFull:        "rejected",
+		octopool.ErrInvalidPoolState: "rejected",
+		octopool.ErrJobDiscarded:     "cancelled",
+		context.Canceled:             "cancelled",
+	}
+
+	for err, outcome := range tests {
+		assert.Equal(t, outcome, Outcome(fmt.Errorf("wrapped: %w", err)), "error %v", err)
+	}
+	assert.Equal(t, "completed", Outcome(nil))
+}
@@ -0,0 +1,166 @@
+// Copyright 2021 Aadhav Vignesh
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octopool
+
+import "time"
+
+// Option is a function for configuring an octopus.
+type Option func(octo *Octopus)
+
+// WithOverflowPolicy sets the policy used when the job queue is full, the default is OverflowReject.
+func WithOverflowPolicy(policy OverflowPolicy) Option {
+	return func(octo *Octopus) {
+		octo.overflowPolicy = policy
+	}
+}
+
+// WithDropHandler sets a function which is called for every job dropped by the overflow policy.
+func WithDropHandler(handler func(job Job)) Option {
+	return func(octo *Octopus) {
+		octo.onDrop = handler
+	}
+}
+
+// WithQueue replaces the job queue with the queue provided, its capacity is used as the queue capacity.
+func WithQueue(queue Queue) Option {
+	return func(octo *Octopus) {
+		octo.jobQueue = queue
+	}
+}
+
+// WithPriorityQueue replaces the FIFO job queue with a priority job queue of the same capacity.
+// Jobs waiting in the queue gain one priority level for every aging interval, zero disables aging.
+func WithPriorityQueue(agingInterval time.Duration) Option {
+	return func(octo *Octopus) {
+		octo.jobQueue = NewPriorityQueue(octo.jobQueue.Capacity(), agingInterval)
+	}
+}
+
+// WithFailFast cancels the remaining jobs once a job fails, like errgroup.
+// Queued jobs are skipped with ErrJobCancelled, and running context-aware jobs have their context cancelled.
+// WaitErr resets the octopus, so that jobs handled afterwards can run again.
+func WithFailFast() Option {
+	return func(octo *Octopus) {
+		octo.failFast = true
+	}
+}
+
+// WithPanicPolicy sets the policy used after a job panics, the default is PanicRecover.
+func WithPanicPolicy(policy PanicPolicy) Option {
+	return func(octo *Octopus) {
+		octo.panicPolicy = policy
+	}
+}
+
+// WithPanicHandler sets a function which is called with the *PanicError of every job which panics.
+func WithPanicHandler(handler func(err *PanicError)) Option {
+	return func(octo *Octopus) {
+		octo.onPanic = handler
+	}
+}
+
+// WithRetryPolicy sets the retry policy for failed jobs, jobs can override it using Job.WithRetry.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(octo *Octopus) {
+		octo.retryPolicy = &policy
+	}
+}
+
+// WithDeadLetterQueue moves jobs which fail permanently, after exhausting their retries or panicking, to a dead-letter queue.
+// The queue holds up to capacity jobs and evicts the oldest one when full, zero means no limit.
+func WithDeadLetterQueue(capacity int) Option {
+	return func(octo *Octopus) {
+		octo.deadLetters = &deadLetterQueue{capacity: capacity}
+	}
+}
+
+// WithJobTimeout sets the timeout for jobs, jobs can override it using Job.WithTimeout.
+// The job's context is cancelled once the timeout expires, and the job fails with ErrJobTimeout.
+func WithJobTimeout(timeout time.Duration) Option {
+	return func(octo *Octopus) {
+		octo.jobTimeout = timeout
+	}
+}
+
+// WithAbandonOnTimeout frees the worker of a job which outlives its timeout, instead of waiting for the job to return.
+// The abandoned job keeps running in its own goroutine, and is reported by AbandonedJobs until it returns.
+func WithAbandonOnTimeout() Option {
+	return func(octo *Octopus) {
+		octo.abandonOnTimeout = true
+	}
+}
+
+// WithClock replaces the system's clock used for scheduled jobs, retries and recurring jobs, e.g. with a FakeClock in tests.
+func WithClock(clock Clock) Option {
+	return func(octo *Octopus) {
+		octo.clock = clock
+	}
+}
+
+// WithAutoscaling scales the pool capacity between a minimum and a maximum, based on the job queue and the busy workers.
+// Idle workers are stopped once they have been parked for longer than the keep-alive duration.
+func WithAutoscaling(config AutoscaleConfig) Option {
+	return func(octo *Octopus) {
+		octo.autoscaler = &autoscaler{config: config}
+	}
+}
+
+// WithAdaptiveLimit adapts the number of jobs which run concurrently to the latencies and errors of finished jobs,
+// within the pool capacity. It suits jobs calling a shared downstream, which fixed concurrency may overload or underuse.
+func WithAdaptiveLimit(config AdaptiveLimitConfig) Option {
+	return func(octo *Octopus) {
+		octo.limiter = newAdaptiveLimiter(config)
+	}
+}
+
+// WithLogger sets the logger receiving levelled, structured messages, the default discards every message.
+// Per-job messages are logged at the debug level. A *slog.Logger can be used directly, or using NewSlogLogger.
+func WithLogger(logger Logger) Option {
+	return func(octo *Octopus) {
+		if logger == nil {
+			logger = nopLogger{}
+		}
+		octo.logger = logger
+	}
+}
+
+// WithMetricsBuckets sets the upper bounds in seconds of the buckets of the wait and execution time histograms,
+// the default is DefaultBuckets.
+func WithMetricsBuckets(buckets []float64) Option {
+	return func(octo *Octopus) {
+		octo.metrics = newMetrics(buckets)
+	}
+}
+
+// WithTracer sets the tracer which traces the lifecycle of every job, see the octotrace package for OpenTelemetry.
+func WithTracer(tracer Tracer) Option {
+	return func(octo *Octopus) {
+		octo.tracer = tracer
+	}
+}
+
+// WithMiddleware adds middleware wrapping the execution of every job, the first middleware being the outermost.
+func WithMiddleware(middleware ...Middleware) Option {
+	return func(octo *Octopus) {
+		octo.middleware = append(octo.middleware, middleware...)
+	}
+}
+
+// WithHooks adds hooks called at every step of the lifecycle of jobs.
+func WithHooks(hooks Hooks) Option {
+	return func(octo *Octopus) {
+		octo.hooks = append(octo.hooks, hooks)
+	}
+}
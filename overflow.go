@@ -0,0 +1,115 @@
+// Copyright 2021 Aadhav Vignesh
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octopool
+
+import (
+	"context"
+)
+
+// OverflowPolicy represents what the octopus does with a job when the job queue is full.
+type OverflowPolicy int
+
+const (
+	// OverflowReject rejects the job with ErrQueueFull
+	OverflowReject OverflowPolicy = 0
+	// OverflowDropOldest drops the oldest job waiting in the job queue to make room for the job,
+	// queues implementing Evicter choose the job to drop, like the priority queue which drops its lowest priority job
+	OverflowDropOldest OverflowPolicy = 1
+	// OverflowDropNewest drops the job which was being handled
+	OverflowDropNewest OverflowPolicy = 2
+	// OverflowCallerRuns executes the job in the goroutine which is handling it
+	OverflowCallerRuns OverflowPolicy = 3
+	// OverflowBlock blocks until a worker or a queue slot is available
+	OverflowBlock OverflowPolicy = 4
+)
+
+// Removes the job dropped by OverflowDropOldest from the job queue.
+func (octo *Octopus) evict() (Job, error) {
+	if evicter, ok := octo.jobQueue.(Evicter); ok {
+		return evicter.Evict()
+	}
+	return octo.jobQueue.Pop()
+}
+
+// Drops the job and reports it to the drop handler.
+func (octo *Octopus) dropJob(job Job) {
+	octo.logger.Warn("dropping job, job queue is full", "job", job.name, "queued", octo.jobQueue.Len())
+	octo.metrics.dropped.Add(1)
+	job.traceEnd(ErrJobDropped)
+	job.handle.finish(nil, ErrJobDropped)
+
+	octo.hookDrop(job)
+	if octo.onDrop != nil {
+		octo.onDrop(job)
+	}
+}
+
+// Executes the job in the calling goroutine.
+func (octo *Octopus) runInCaller(job Job) {
+	octo.logger.Warn("running job in the caller, job queue is full", "job", job.name, "queued", octo.jobQueue.Len())
+
+	// the job is pending while it runs, as it may be retried by the workers
+	octo.workerPool.addPendingJob()
+	octo.metrics.accepted(job)
+	if octo.runJob(job) {
+		octo.workerPool.donePendingJob()
+	}
+}
+
+// Blocks until space may be available, ctx is done or the pool is closed, ctx may be nil.
+func (octo *Octopus) blockOn(ctx context.Context, space <-chan struct{}) error {
+	// the pool may have been closed before the space channel was taken
+	if octo.workerPool.isClosed() {
+		return ErrInvalidPoolState
+	}
+
+	var done <-chan struct{}
+	if ctx != nil {
+		done = ctx.Done()
+	}
+
+	select {
+	case <-space:
+	case <-done:
+		return ctx.Err()
+	}
+
+	if octo.workerPool.isClosed() {
+		return ErrInvalidPoolState
+	}
+	return nil
+}
+
+// Returns a channel which is closed once a worker or a queue slot may have been freed.
+func (octo *Octopus) waitForSpace() <-chan struct{} {
+	octo.spaceMu.Lock()
+	defer octo.spaceMu.Unlock()
+
+	if octo.space == nil {
+		octo.space = make(chan struct{})
+	}
+	return octo.space
+}
+
+// Wakes up every job waiting for space.
+func (octo *Octopus) signalSpace() {
+	octo.spaceMu.Lock()
+	defer octo.spaceMu.Unlock()
+
+	if octo.space != nil {
+		close(octo.space)
+		octo.space = nil
+	}
+}
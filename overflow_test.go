@@ -0,0 +1,224 @@
+// Copyright 2021 Aadhav Vignesh
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octopool
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Returns an octopus with a single worker which is busy until release is closed.
+func newBusyOctopus(t *testing.T, queueCapacity int, opts ...Option) (*Octopus, chan struct{}) {
+	testOctopus := NewOctopusWithOptions(1, queueCapacity, opts...)
+
+	release := make(chan struct{})
+	err := testOctopus.HandleJob(func() { <-release }, "blocker")
+	if err != nil {
+		t.Fatalf("Got error while handling job: %v", err)
+	}
+
+	return testOctopus, release
+}
+
+// Test for checking that a full job queue rejects jobs by default.
+func TestOverflowReject(t *testing.T) {
+	testOctopus, release := newBusyOctopus(t, 1)
+	defer close(release)
+
+	err := testOctopus.HandleJob(func() {}, "job 1")
+	assert.NoError(t, err)
+
+	err = testOctopus.HandleJob(func() {}, "job 2")
+	assert.Equal(t, ErrQueueFull, err)
+	assert.Equal(t, 1, testOctopus.jobQueue.Len())
+}
+
+// Test for checking that the oldest job is dropped and reported.
+func TestOverflowDropOldest(t *testing.T) {
+	var mu sync.Mutex
+	var dropped []string
+	testOctopus, release := newBusyOctopus(t, 1,
+		WithOverflowPolicy(OverflowDropOldest),
+		WithDropHandler(func(job Job) {
+			mu.Lock()
+			dropped = append(dropped, job.Name())
+			mu.Unlock()
+		}),
+	)
+
+	handle, err := testOctopus.Submit(func() (interface{}, error) { return nil, nil }, "job 1")
+	assert.NoError(t, err)
+
+	err = testOctopus.HandleJob(func() {}, "job 2")
+	assert.NoError(t, err)
+
+	assert.Equal(t, ErrJobDropped, handle.Err())
+
+	close(release)
+	testOctopus.Wait()
+
+	assert.Equal(t, []string{"job 1"}, dropped)
+}
+
+// drainedQueue is a struct for representing a queue which workers empty right after it reported being full.
+type drainedQueue struct {
+	Queue
+	full bool // reports whether the next push fails with ErrQueueFull
+}
+
+// Push fails with ErrQueueFull once, as if the queue was full, then adds the job to the queue.
+func (queue *drainedQueue) Push(job Job) error {
+	if queue.full {
+		queue.full = false
+		return ErrQueueFull
+	}
+	return queue.Queue.Push(job)
+}
+
+// Test for checking that the job is queued rather than dropped if the queue was emptied before dropping the oldest job.
+func TestOverflowDropOldestDrained(t *testing.T) {
+	var dropped int32
+	queue := &drainedQueue{Queue: NewJobQueue(1)}
+	testOctopus, release := newBusyOctopus(t, 1,
+		WithQueue(queue),
+		WithOverflowPolicy(OverflowDropOldest),
+		WithDropHandler(func(job Job) { atomic.AddInt32(&dropped, 1) }),
+	)
+
+	queue.full = true
+	handle, err := testOctopus.Submit(func() (interface{}, error) { return 42, nil }, "job")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, queue.Len())
+
+	close(release)
+	result, err := handle.Wait()
+	assert.NoError(t, err)
+	assert.Equal(t, 42, result)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&dropped))
+}
+
+// Test for checking that a full priority queue drops its lowest priority job, rather than its next job.
+func TestOverflowDropOldestPriority(t *testing.T) {
+	var dropped []string
+	testOctopus, release := newBusyOctopus(t, 2,
+		WithPriorityQueue(0),
+		WithOverflowPolicy(OverflowDropOldest),
+		WithDropHandler(func(job Job) {
+			dropped = append(dropped, job.Name())
+		}),
+	)
+
+	assert.NoError(t, testOctopus.HandleJobPriority(func() {}, 1, "low"))
+	assert.NoError(t, testOctopus.HandleJobPriority(func() {}, 10, "high"))
+	assert.NoError(t, testOctopus.HandleJobPriority(func() {}, 5, "medium"))
+
+	assert.Equal(t, []string{"low"}, dropped)
+	assert.Equal(t, []string{"high", "medium"}, drainNames(t, testOctopus.jobQueue))
+	close(release)
+}
+
+// Test for checking that the newest job is dropped and reported.
+func TestOverflowDropNewest(t *testing.T) {
+	var dropped []string
+	testOctopus, release := newBusyOctopus(t, 1,
+		WithOverflowPolicy(OverflowDropNewest),
+		WithDropHandler(func(job Job) {
+			dropped = append(dropped, job.Name())
+		}),
+	)
+	defer close(release)
+
+	err := testOctopus.HandleJob(func() {}, "job 1")
+	assert.NoError(t, err)
+
+	handle, err := testOctopus.Submit(func() (interface{}, error) { return nil, nil }, "job 2")
+	assert.NoError(t, err)
+
+	assert.Equal(t, ErrJobDropped, handle.Err())
+	assert.Equal(t, []string{"job 2"}, dropped)
+	assert.Equal(t, 1, testOctopus.jobQueue.Len())
+}
+
+// Test for checking that the job runs in the caller when the job queue is full.
+func TestOverflowCallerRuns(t *testing.T) {
+	testOctopus, release := newBusyOctopus(t, 0, WithOverflowPolicy(OverflowCallerRuns))
+	defer close(release)
+
+	ran := false
+	err := testOctopus.HandleJob(func() { ran = true }, "job 1")
+
+	assert.NoError(t, err)
+	assert.True(t, ran, "job should have run in the caller.")
+
+	handle, err := testOctopus.Submit(func() (interface{}, error) { panic("octopus down") }, "job 2")
+	assert.NoError(t, err)
+	assert.True(t, errors.Is(handle.Err(), ErrJobPanicked))
+}
+
+// Test for checking that the job blocks until a worker is available.
+func TestOverflowBlock(t *testing.T) {
+	testOctopus, release := newBusyOctopus(t, 0, WithOverflowPolicy(OverflowBlock))
+
+	handled := make(chan error)
+	go func() {
+		handled <- testOctopus.HandleJob(func() {}, "job 1")
+	}()
+
+	select {
+	case <-handled:
+		t.Fatal("job should block while the pool is full.")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+
+	assert.NoError(t, <-handled)
+	testOctopus.Wait()
+}
+
+// Test for checking that a blocked job gives up when its context is done.
+func TestOverflowBlockContext(t *testing.T) {
+	testOctopus, release := newBusyOctopus(t, 0, WithOverflowPolicy(OverflowBlock))
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := testOctopus.HandleJobContext(ctx, func(ctx context.Context) error { return nil }, "job 1")
+
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
+
+// Test for checking that a blocked job gives up when the pool is closed.
+func TestOverflowBlockClose(t *testing.T) {
+	testOctopus, release := newBusyOctopus(t, 0, WithOverflowPolicy(OverflowBlock))
+	defer close(release)
+
+	handled := make(chan error)
+	go func() {
+		handled <- testOctopus.HandleJob(func() {}, "job 1")
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	testOctopus.Close()
+
+	assert.Equal(t, ErrInvalidPoolState, <-handled)
+}
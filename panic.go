@@ -0,0 +1,95 @@
+// Copyright 2021 Aadhav Vignesh
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octopool
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicPolicy represents what the octopus does after a job panics.
+type PanicPolicy int
+
+const (
+	// PanicRecover recovers from the panic, and the worker continues with the next job
+	PanicRecover PanicPolicy = 0
+	// PanicRepanic panics again with the *PanicError, which crashes the program unless recovered by the caller
+	PanicRepanic PanicPolicy = 1
+	// PanicShutdown recovers from the panic, and shuts the octopus down, discarding the job queue
+	PanicShutdown PanicPolicy = 2
+)
+
+// PanicError is a struct for representing a panic raised by a job.
+type PanicError struct {
+	Name  string      // name of the job
+	Value interface{} // value the job panicked with
+	Stack []byte      // stack trace of the panicking goroutine
+}
+
+// Formats PanicError struct.
+func (err *PanicError) Error() string {
+	return fmt.Sprintf("job: %s panicked: %v", err.Name, err.Value)
+}
+
+// Is reports whether the target is ErrJobPanicked, so that panics can be detected using errors.Is.
+func (err *PanicError) Is(target error) bool {
+	return target == ErrJobPanicked
+}
+
+// Unwrap returns the value the job panicked with, if it is an error.
+func (err *PanicError) Unwrap() error {
+	if valueErr, ok := err.Value.(error); ok {
+		return valueErr
+	}
+	return nil
+}
+
+// Handles a value recovered from a panicking job, and returns the *PanicError delivered to the job's handle.
+// It must be called from the deferred function which recovered, so that the stack trace points at the panic.
+func (octo *Octopus) handlePanic(job Job, recovered interface{}) *PanicError {
+	panicErr := &PanicError{
+		Name:  job.name,
+		Value: recovered,
+		Stack: debug.Stack(),
+	}
+
+	// the job panicked in its own goroutine, keep its stack trace
+	if carried, ok := recovered.(*recoveredPanic); ok {
+		panicErr.Value, panicErr.Stack = carried.value, carried.stack
+	}
+
+	octo.logger.Error("recovered panicking job", "job", job.name, "panic", recovered, "stack", string(panicErr.Stack))
+
+	// report the failure to the job's handle and the panic handler
+	octo.metrics.panicked.Add(1)
+	job.handle.finish(nil, panicErr)
+	if octo.onPanic != nil {
+		octo.onPanic(panicErr)
+	}
+	octo.hookPanic(job, panicErr)
+
+	return panicErr
+}
+
+// Applies the panic policy once the panic has been handled.
+func (octo *Octopus) applyPanicPolicy(panicErr *PanicError) {
+	switch octo.panicPolicy {
+	case PanicRepanic:
+		panic(panicErr)
+	case PanicShutdown:
+		octo.logger.Error("shutting down after a job panicked", "job", panicErr.Name)
+		octo.ShutdownNow()
+	}
+}
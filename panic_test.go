@@ -0,0 +1,115 @@
+// Copyright 2021 Aadhav Vignesh
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octopool
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test for checking that a panic is delivered to the job's handle as a *PanicError.
+func TestPanicError(t *testing.T) {
+	testOctopus := NewOctopus(1)
+
+	handle, err := testOctopus.Submit(func() (interface{}, error) {
+		panic("octopus down")
+	}, "panicking")
+	if err != nil {
+		t.Fatalf("Got error while submitting job: %v", err)
+	}
+
+	var panicErr *PanicError
+	assert.True(t, errors.As(handle.Err(), &panicErr))
+	assert.True(t, errors.Is(handle.Err(), ErrJobPanicked))
+	assert.Equal(t, "panicking", panicErr.Name)
+	assert.Equal(t, "octopus down", panicErr.Value)
+	assert.Contains(t, string(panicErr.Stack), "TestPanicError")
+	assert.Equal(t, "job: panicking panicked: octopus down", panicErr.Error())
+}
+
+// Test for checking that a panic with an error value can be matched against that error.
+func TestPanicErrorUnwrap(t *testing.T) {
+	testOctopus := NewOctopus(1)
+	errBoom := errors.New("boom")
+
+	handle, err := testOctopus.Submit(func() (interface{}, error) {
+		panic(errBoom)
+	}, "panicking")
+	if err != nil {
+		t.Fatalf("Got error while submitting job: %v", err)
+	}
+
+	assert.True(t, errors.Is(handle.Err(), errBoom))
+}
+
+// Test for checking that the panic handler receives every panic.
+func TestPanicHandler(t *testing.T) {
+	var mu sync.Mutex
+	var panicked []string
+	testOctopus := NewOctopusWithOptions(2, queueCapacity, WithPanicHandler(func(err *PanicError) {
+		mu.Lock()
+		panicked = append(panicked, err.Name)
+		mu.Unlock()
+	}))
+
+	assert.NoError(t, testOctopus.HandleJob(func() { panic("first") }, "first"))
+	testOctopus.Wait()
+	assert.NoError(t, testOctopus.HandleJob(func() { panic("second") }, "second"))
+	testOctopus.Wait()
+
+	assert.Equal(t, []string{"first", "second"}, panicked)
+	assert.Equal(t, 2, testOctopus.AvailableWorkers(), "workers should recover by default.")
+}
+
+// Test for checking that the octopus shuts down after a panic with PanicShutdown.
+func TestPanicPolicyShutdown(t *testing.T) {
+	testOctopus := NewOctopusWithOptions(1, queueCapacity, WithPanicPolicy(PanicShutdown))
+
+	release := make(chan struct{})
+	assert.NoError(t, testOctopus.HandleJob(func() {
+		<-release
+		panic("octopus down")
+	}, "panicking"))
+
+	handle, err := testOctopus.Submit(func() (interface{}, error) { return nil, nil }, "queued")
+	assert.NoError(t, err)
+
+	close(release)
+	testOctopus.Wait()
+
+	assert.Equal(t, ErrJobDiscarded, handle.Err())
+	assert.Equal(t, ErrInvalidPoolState, testOctopus.HandleJob(func() {}, "late"))
+}
+
+// Test for checking that the panic is raised again with PanicRepanic.
+func TestPanicPolicyRepanic(t *testing.T) {
+	// run the job in the caller, so that the panic can be observed by the test
+	testOctopus, release := newBusyOctopus(t, 0, WithOverflowPolicy(OverflowCallerRuns), WithPanicPolicy(PanicRepanic))
+	defer close(release)
+
+	defer func() {
+		r := recover()
+
+		panicErr, ok := r.(*PanicError)
+		assert.True(t, ok, "panic should be raised again with a *PanicError.")
+		assert.Equal(t, "panicking", panicErr.Name)
+	}()
+
+	_ = testOctopus.HandleJob(func() { panic("octopus down") }, "panicking")
+	t.Error("HandleJob should panic.")
+}
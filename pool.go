@@ -14,7 +14,10 @@
 
 package octopool
 
-import "sync"
+import (
+	"sync"
+	"sync/atomic"
+)
 
 // Represents state for the pool.
 type state int
@@ -28,31 +31,83 @@ const (
 )
 
 type pool struct {
-	status           state          // represents current state of the pool
-	capacity         int            // number of workers the pool can accommodate
-	availableWorkers sync.Pool      // pool of available workers
-	activeWorkers    int            // number of active workers
-	closePool        sync.Once      // closes pool and can be called only once
-	mu               sync.Mutex     // mutex for locking
-	octopus          *Octopus       // provides an API to interact with the pool
-	wg               sync.WaitGroup // used to wait for multiple goroutines to finish
+	status           state         // represents current state of the pool
+	capacity         int           // number of workers the pool can accommodate
+	idleWorkers      []*worker     // parked workers waiting for a job, most recently parked last
+	spawnedWorkers   int           // number of running worker goroutines, busy or idle
+	activeWorkers    int           // number of active workers
+	abandonedWorkers atomic.Int64  // number of goroutines running abandoned jobs, not counted as active workers
+	pendingJobs      int           // number of accepted jobs which have not finished yet
+	idle             chan struct{} // closed once there are no pending jobs
+	closePool        sync.Once     // closes pool and can be called only once
+	mu               sync.Mutex    // mutex for locking
+	octopus          *Octopus      // provides an API to interact with the pool
+	stats            poolStats     // statistics which can be read without locking the pool
 }
 
 // Basic helper functions:
 
 // Returns pool's capacity.
 func (p *pool) getPoolCapacity() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
 	return p.capacity
 }
 
 // Returns number of active workers.
 func (p *pool) getActiveWorkersCount() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
 	return p.activeWorkers
 }
 
-// Checks if a worker is available or not.
+// Returns number of goroutines running abandoned jobs.
+func (p *pool) getAbandonedWorkersCount() int {
+	return int(p.abandonedWorkers.Load())
+}
+
+// Returns number of available workers.
+func (p *pool) getAvailableWorkersCount() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// busy workers above a shrunk capacity or limit are still active
+	if limit := p.limit(); p.activeWorkers < limit {
+		return limit - p.activeWorkers
+	}
+	return 0
+}
+
+// Returns the number of workers which can be active.
+func (p *pool) getLimit() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.limit()
+}
+
+// Returns the number of workers which can be active, bounded by the adaptive limit if there is one.
+// The pool's lock must be held.
+func (p *pool) limit() int {
+	if p.octopus.limiter == nil {
+		return p.capacity
+	}
+	return min(p.capacity, p.octopus.limiter.currentLimit())
+}
+
+// Checks if a worker is available or not, the pool's lock must be held.
 func (p *pool) isWorkerAvailable() bool {
-	return p.activeWorkers < p.capacity
+	return p.activeWorkers < p.limit()
+}
+
+// Checks if the pool is closed.
+func (p *pool) isClosed() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.status == PoolClosed
 }
 
 // Pool-related functions:
@@ -62,60 +117,183 @@ func newPool(capacity int, octopus *Octopus) *pool {
 	newPool := pool{
 		status:   PoolOpen,
 		capacity: capacity,
-		availableWorkers: sync.Pool{
-			New: func() interface{} {
-				return new(worker)
-			},
-		},
-		octopus: octopus,
+		octopus:  octopus,
 	}
 
 	return &newPool
 }
 
-// Assigns a job to a worker.
+// Reserves a worker for a job, returns false if no worker is available.
+// The check and the reservation happen under one lock, so that concurrent callers cannot overfill the pool.
+func (p *pool) acquireWorker() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.isWorkerAvailable() {
+		return false
+	}
+
+	p.activeWorkers++
+	p.stats.setActiveWorkers(p.activeWorkers)
+	return true
+}
+
+// Checks if a busy worker can keep its reservation for the next job, which is not the case above a shrunk capacity or limit.
+func (p *pool) canKeepWorker() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.activeWorkers <= p.limit()
+}
+
+// Releases a worker reserved by acquireWorker.
+func (p *pool) releaseWorker() {
+	p.mu.Lock()
+	p.activeWorkers--
+	p.stats.setActiveWorkers(p.activeWorkers)
+	p.mu.Unlock()
+}
+
+// Assigns a job to a worker reserved by acquireWorker.
+// An idle worker is reused if there is one, else, a new worker goroutine is started.
 func (p *pool) assignJob(job Job) {
-	// put lock on pool
 	p.mu.Lock()
 
+	if last := len(p.idleWorkers) - 1; last >= 0 {
+		// wake up the most recently parked worker
+		w := p.idleWorkers[last]
+		p.idleWorkers[last] = nil
+		p.idleWorkers = p.idleWorkers[:last]
+		p.mu.Unlock()
+
+		w.jobs <- job
+		return
+	}
+
+	p.spawnedWorkers++
+	p.mu.Unlock()
+
+	w := &worker{jobs: make(chan Job, 1), pool: p}
+	go w.run(job)
+}
+
+// Housekeeping function; releases the worker's reservation and parks it until it is assigned a new job.
+// Returns false if the worker should exit instead, as the pool is closed.
+func (p *pool) parkWorker(w *worker) bool {
+	p.mu.Lock()
 	defer p.mu.Unlock()
 
-	// tell the pool to give a worker to assign a job
-	worker := p.availableWorkers.Get().(*worker)
+	p.activeWorkers--
+	p.stats.setActiveWorkers(p.activeWorkers)
+
+	// stop the worker if the pool is closed, or if it would exceed a shrunk capacity
+	if p.status == PoolClosed || len(p.idleWorkers) >= p.capacity {
+		p.spawnedWorkers--
+		return false
+	}
 
-	// make channel for job
-	worker.jobs = make(chan func())
+	w.parkedAt = p.octopus.clock.Now()
+	p.idleWorkers = append(p.idleWorkers, w)
+	return true
+}
 
-	// set pool for worker
-	worker.pool = p
+// Records a goroutine left running an abandoned job.
+func (p *pool) abandonWorker() {
+	p.abandonedWorkers.Add(1)
+}
 
-	// run worker
-	worker.run()
+// Records an abandoned job which returned.
+func (p *pool) doneAbandonedWorker() {
+	p.abandonedWorkers.Add(-1)
+}
 
-	// get the job and send it to the jobs channel
-	worker.jobs <- job.getJob()
+// Records a job accepted by the octopus.
+func (p *pool) addPendingJob() {
+	p.mu.Lock()
+	p.pendingJobs++
+	p.mu.Unlock()
+}
 
-	// increment active worker count
-	p.activeWorkers++
+// Records a job which finished or will never run, and wakes up the waiters once no jobs are pending.
+func (p *pool) donePendingJob() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.pendingJobs--
+	if p.pendingJobs == 0 && p.idle != nil {
+		close(p.idle)
+		p.idle = nil
+	}
 }
 
-// Housekeeping function; adds worker to availableWorkers.
-func (p *pool) newWorkerAvailable(w *worker) {
-	// add worker to available workers
-	p.availableWorkers.Put(new(worker))
+// Returns a channel which is closed once there are no pending jobs.
+func (p *pool) idleSignal() <-chan struct{} {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.idle == nil {
+		p.idle = make(chan struct{})
+	}
+	idle := p.idle
 
-	// decrement active worker count using mutex
+	if p.pendingJobs == 0 {
+		close(p.idle)
+		p.idle = nil
+	}
+	return idle
+}
+
+// Blocks until there are no pending jobs.
+func (p *pool) wait() {
+	<-p.idleSignal()
+}
+
+// Returns number of pending jobs.
+func (p *pool) getPendingJobsCount() int {
 	p.mu.Lock()
-	p.activeWorkers--
+	defer p.mu.Unlock()
+
+	return p.pendingJobs
+}
+
+// Changes the number of workers the pool can accommodate.
+// Surplus idle workers are stopped, busy workers above the capacity stop once their job is finished.
+func (p *pool) resize(capacity int) {
+	p.mu.Lock()
+	p.capacity = capacity
+
+	// stop the least recently parked idle workers
+	var stopped []*worker
+	if surplus := len(p.idleWorkers) - capacity; surplus > 0 {
+		stopped = make([]*worker, surplus)
+		copy(stopped, p.idleWorkers)
+		p.idleWorkers = append(p.idleWorkers[:0], p.idleWorkers[surplus:]...)
+		p.spawnedWorkers -= surplus
+	}
 	p.mu.Unlock()
 
-	// let octopus handle the next job
-	p.octopus.processNext()
+	for _, w := range stopped {
+		close(w.jobs)
+	}
 }
 
 // Sets status to PoolClosed.
 func (p *pool) close() {
 	p.closePool.Do(func() {
+		p.mu.Lock()
 		p.status = PoolClosed
+
+		// stop the idle workers, busy workers exit once the job queue is drained
+		idleWorkers := p.idleWorkers
+		p.idleWorkers = nil
+		p.spawnedWorkers -= len(idleWorkers)
+		p.mu.Unlock()
+
+		for _, w := range idleWorkers {
+			close(w.jobs)
+		}
 	})
+
+	// wake up jobs blocked on a full job queue
+	p.octopus.signalSpace()
 }
@@ -0,0 +1,200 @@
+// Copyright 2021 Aadhav Vignesh
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octopool
+
+import (
+	"container/heap"
+	"math"
+	"sync"
+	"time"
+)
+
+// jobHeap is a struct for representing jobs ordered by their aged priority.
+//
+// A job's priority grows by one for every aging interval it spends in the queue.
+// As every job ages at the same rate, the order between two jobs never changes
+// while they wait, so a heap keyed on the priority and the enqueue time is enough.
+type jobHeap struct {
+	jobs          []Job         // jobs in heap order
+	agingInterval time.Duration // time after which a waiting job's priority grows by one, zero disables aging
+	start         time.Time     // reference time for the enqueue times
+	sequence      uint64        // sequence for the next job, keeps FIFO order between equal priorities
+}
+
+// PriorityQueue is a struct for representing a queue which holds pending jobs ordered by priority.
+// It is safe for concurrent use.
+type PriorityQueue struct {
+	jobs     jobHeap    // jobs ordered by their aged priority
+	capacity int        // total capacity of the queue
+	closed   bool       // represents if the queue accepts new jobs
+	mu       sync.Mutex // mutex for locking
+}
+
+// NewPriorityQueue returns a queue with the specified capacity, which always removes the job with the highest priority.
+// Jobs waiting in the queue gain one priority level for every aging interval, so that low-priority jobs eventually run.
+// An aging interval equal to or less than zero disables aging.
+func NewPriorityQueue(queueCapacity int, agingInterval time.Duration) *PriorityQueue {
+	if agingInterval < 0 {
+		agingInterval = 0
+	}
+
+	priorityQueue := PriorityQueue{
+		jobs: jobHeap{
+			jobs:          make([]Job, 0, queueCapacity),
+			agingInterval: agingInterval,
+			start:         time.Now(),
+		},
+		capacity: queueCapacity,
+	}
+	return &priorityQueue
+}
+
+// Push adds a job to the queue.
+func (priorityQueue *PriorityQueue) Push(job Job) error {
+	priorityQueue.mu.Lock()
+	defer priorityQueue.mu.Unlock()
+
+	if priorityQueue.closed {
+		return ErrQueueClosed
+	}
+
+	if len(priorityQueue.jobs.jobs) >= priorityQueue.capacity {
+		return ErrQueueFull
+	}
+
+	heap.Push(&priorityQueue.jobs, priorityQueue.jobs.stamp(job))
+	return nil
+}
+
+// Pop removes the job with the highest priority from the queue.
+func (priorityQueue *PriorityQueue) Pop() (Job, error) {
+	priorityQueue.mu.Lock()
+	defer priorityQueue.mu.Unlock()
+
+	if priorityQueue.jobs.Len() == 0 {
+		return Job{}, ErrQueueEmpty
+	}
+
+	return heap.Pop(&priorityQueue.jobs).(Job), nil
+}
+
+// Evict removes the job with the lowest aged priority from the queue, which is the job that would run last.
+// It is used by OverflowDropOldest, so that a full queue drops its least important job rather than its most important one.
+func (priorityQueue *PriorityQueue) Evict() (Job, error) {
+	priorityQueue.mu.Lock()
+	defer priorityQueue.mu.Unlock()
+
+	if priorityQueue.jobs.Len() == 0 {
+		return Job{}, ErrQueueEmpty
+	}
+
+	// the job running last is a leaf of the heap, search every job as the leaves are not ordered
+	last := 0
+	for i := 1; i < priorityQueue.jobs.Len(); i++ {
+		if priorityQueue.jobs.Less(last, i) {
+			last = i
+		}
+	}
+
+	return heap.Remove(&priorityQueue.jobs, last).(Job), nil
+}
+
+// Len returns the number of jobs in the queue.
+func (priorityQueue *PriorityQueue) Len() int {
+	priorityQueue.mu.Lock()
+	defer priorityQueue.mu.Unlock()
+
+	return priorityQueue.jobs.Len()
+}
+
+// Capacity returns the queue's capacity.
+func (priorityQueue *PriorityQueue) Capacity() int {
+	return priorityQueue.capacity
+}
+
+// Close stops the queue from accepting new jobs.
+func (priorityQueue *PriorityQueue) Close() {
+	priorityQueue.mu.Lock()
+	defer priorityQueue.mu.Unlock()
+
+	priorityQueue.closed = true
+}
+
+// Records the enqueue time and sequence of the job.
+func (h *jobHeap) stamp(job Job) Job {
+	job.enqueuedAt = time.Now()
+	job.sequence = h.sequence
+	h.sequence++
+	return job
+}
+
+// Returns the job's aged priority, scaled by the aging interval and relative to the heap's start.
+// The key saturates instead of overflowing, so that extreme priorities keep their order.
+func (h *jobHeap) key(job Job) int64 {
+	priority, interval := int64(job.priority), int64(h.agingInterval)
+	waited := int64(job.enqueuedAt.Sub(h.start))
+
+	switch {
+	case priority > math.MaxInt64/interval:
+		return math.MaxInt64 - waited
+	case priority < math.MinInt64/interval:
+		return math.MinInt64
+	}
+
+	scaled := priority * interval
+	if scaled < math.MinInt64+waited {
+		return math.MinInt64
+	}
+	return scaled - waited
+}
+
+// Len is the number of jobs in the heap.
+func (h *jobHeap) Len() int {
+	return len(h.jobs)
+}
+
+// Less reports whether job i should run before job j.
+func (h *jobHeap) Less(i, j int) bool {
+	a, b := h.jobs[i], h.jobs[j]
+
+	if h.agingInterval > 0 {
+		if ka, kb := h.key(a), h.key(b); ka != kb {
+			return ka > kb
+		}
+	} else if a.priority != b.priority {
+		return a.priority > b.priority
+	}
+
+	return a.sequence < b.sequence
+}
+
+// Swap swaps jobs i and j.
+func (h *jobHeap) Swap(i, j int) {
+	h.jobs[i], h.jobs[j] = h.jobs[j], h.jobs[i]
+}
+
+// Push adds a job to the heap.
+func (h *jobHeap) Push(x interface{}) {
+	h.jobs = append(h.jobs, x.(Job))
+}
+
+// Pop removes the last job from the heap.
+func (h *jobHeap) Pop() interface{} {
+	last := len(h.jobs) - 1
+	job := h.jobs[last]
+	h.jobs[last] = Job{}
+	h.jobs = h.jobs[:last]
+	return job
+}
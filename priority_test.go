@@ -0,0 +1,149 @@
+// Copyright 2021 Aadhav Vignesh
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octopool
+
+import (
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Returns the names of the jobs in the order they are removed from the queue.
+func drainNames(t *testing.T, testQueue Queue) []string {
+	var names []string
+	for testQueue.Len() > 0 {
+		job, err := testQueue.Pop()
+		if err != nil {
+			t.Fatalf("Got error: %v", err)
+		}
+		names = append(names, job.Name())
+	}
+	return names
+}
+
+// Test for checking that the job with the highest priority is removed first.
+func TestPriorityJobQueueOrder(t *testing.T) {
+	testQueue := NewPriorityQueue(queueCapacity, 0)
+
+	assert.NoError(t, testQueue.Push(NewJob(func() {}).WithName("low").WithPriority(1)))
+	assert.NoError(t, testQueue.Push(NewJob(func() {}).WithName("high").WithPriority(10)))
+	assert.NoError(t, testQueue.Push(NewJob(func() {}).WithName("medium").WithPriority(5)))
+
+	assert.Equal(t, []string{"high", "medium", "low"}, drainNames(t, testQueue))
+}
+
+// Test for checking that jobs with equal priorities are removed in FIFO order.
+func TestPriorityJobQueueFIFO(t *testing.T) {
+	testQueue := NewPriorityQueue(queueCapacity, 0)
+
+	for _, name := range []string{"first", "second", "third"} {
+		assert.NoError(t, testQueue.Push(NewJob(func() {}).WithName(name)))
+	}
+
+	assert.Equal(t, []string{"first", "second", "third"}, drainNames(t, testQueue))
+}
+
+// Test for checking that a long-waiting job is aged above newer jobs with a higher priority.
+func TestPriorityJobQueueAging(t *testing.T) {
+	testQueue := NewPriorityQueue(queueCapacity, 10*time.Millisecond)
+
+	assert.NoError(t, testQueue.Push(NewJob(func() {}).WithName("old").WithPriority(0)))
+	time.Sleep(50 * time.Millisecond)
+	assert.NoError(t, testQueue.Push(NewJob(func() {}).WithName("new").WithPriority(2)))
+	assert.NoError(t, testQueue.Push(NewJob(func() {}).WithName("urgent").WithPriority(100)))
+
+	assert.Equal(t, []string{"urgent", "old", "new"}, drainNames(t, testQueue))
+}
+
+// Test for checking that extreme priorities keep their order when aging scales them.
+func TestPriorityJobQueueAgingExtreme(t *testing.T) {
+	testQueue := NewPriorityQueue(queueCapacity, time.Second)
+
+	assert.NoError(t, testQueue.Push(NewJob(func() {}).WithName("lowest").WithPriority(math.MinInt)))
+	assert.NoError(t, testQueue.Push(NewJob(func() {}).WithName("normal").WithPriority(0)))
+	assert.NoError(t, testQueue.Push(NewJob(func() {}).WithName("highest").WithPriority(math.MaxInt)))
+	assert.NoError(t, testQueue.Push(NewJob(func() {}).WithName("high").WithPriority(1<<20)))
+
+	assert.Equal(t, []string{"highest", "high", "normal", "lowest"}, drainNames(t, testQueue))
+}
+
+// Test for checking that a full priority queue rejects jobs.
+func TestPriorityJobQueueFull(t *testing.T) {
+	testQueue := NewPriorityQueue(1, 0)
+
+	assert.NoError(t, testQueue.Push(NewJob(func() {})))
+	assert.Equal(t, ErrQueueFull, testQueue.Push(NewJob(func() {})))
+}
+
+// Test for checking that the job which would run last is evicted.
+func TestPriorityJobQueueEvict(t *testing.T) {
+	testQueue := NewPriorityQueue(queueCapacity, 0)
+
+	_, err := testQueue.Evict()
+	assert.Equal(t, ErrQueueEmpty, err)
+
+	for _, job := range []Job{
+		NewJob(func() {}).WithName("high").WithPriority(10),
+		NewJob(func() {}).WithName("low first").WithPriority(1),
+		NewJob(func() {}).WithName("medium").WithPriority(5),
+		NewJob(func() {}).WithName("low second").WithPriority(1),
+		NewJob(func() {}).WithName("urgent").WithPriority(100),
+	} {
+		assert.NoError(t, testQueue.Push(job))
+	}
+
+	evicted, err := testQueue.Evict()
+	assert.NoError(t, err)
+	assert.Equal(t, "low second", evicted.Name())
+
+	evicted, err = testQueue.Evict()
+	assert.NoError(t, err)
+	assert.Equal(t, "low first", evicted.Name())
+
+	assert.Equal(t, []string{"urgent", "high", "medium"}, drainNames(t, testQueue))
+}
+
+// Test for checking that the octopus promotes the job with the highest priority.
+func TestOctopusPriorityQueue(t *testing.T) {
+	testOctopus := NewOctopusWithOptions(1, queueCapacity, WithPriorityQueue(0))
+
+	release := make(chan struct{})
+	err := testOctopus.HandleJob(func() { <-release }, "blocker")
+	if err != nil {
+		t.Fatalf("Got error while handling job: %v", err)
+	}
+
+	var mu sync.Mutex
+	var order []string
+	record := func(name string) func() {
+		return func() {
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+		}
+	}
+
+	assert.NoError(t, testOctopus.HandleJobPriority(record("batch"), 0, "batch"))
+	assert.NoError(t, testOctopus.HandleJobPriority(record("interactive"), 10, "interactive"))
+	assert.True(t, testOctopus.TrySubmit(NewJob(record("normal")).WithPriority(5)))
+
+	close(release)
+	testOctopus.Wait()
+
+	assert.Equal(t, []string{"interactive", "normal", "batch"}, order)
+}
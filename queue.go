@@ -0,0 +1,51 @@
+// Copyright 2021 Aadhav Vignesh
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octopool
+
+import "errors"
+
+// ErrQueueFull is the error raised when the job queue is full and the overflow policy rejects the job.
+var ErrQueueFull = errors.New("job queue is full")
+
+// ErrQueueEmpty is the error raised when a job is removed from an empty job queue.
+var ErrQueueEmpty = errors.New("empty job queue")
+
+// ErrQueueClosed is the error raised when a job is added to a closed job queue.
+var ErrQueueClosed = errors.New("job queue is closed")
+
+// Queue is an interface for representing a queue which holds pending jobs.
+//
+// The octopus depends on this interface for its job queue, so that custom queues
+// can be plugged in using WithQueue. Implementations must be safe for concurrent use,
+// as jobs are added by submitters and removed by workers at the same time.
+type Queue interface {
+	// Push adds a job to the queue, returns ErrQueueFull if the queue is full and ErrQueueClosed if the queue is closed.
+	Push(job Job) error
+	// Pop removes the next job from the queue, returns ErrQueueEmpty if the queue is empty.
+	Pop() (Job, error)
+	// Len returns the number of jobs in the queue.
+	Len() int
+	// Capacity returns the maximum number of jobs the queue can hold.
+	Capacity() int
+	// Close stops the queue from accepting new jobs, jobs already in the queue can still be removed.
+	Close()
+}
+
+// Evicter is an interface for queues which choose the job dropped by OverflowDropOldest.
+// Queues which do not implement it drop the next job they would remove, which is the oldest one in a FIFO queue.
+type Evicter interface {
+	// Evict removes the job which is the least worth running, returns ErrQueueEmpty if the queue is empty.
+	Evict() (Job, error)
+}
@@ -0,0 +1,160 @@
+// Copyright 2021 Aadhav Vignesh
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octopool
+
+import (
+	"runtime"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Returns every built-in queue with the specified capacity.
+func builtinQueues(capacity int) map[string]Queue {
+	return map[string]Queue{
+		"job queue":      NewJobQueue(capacity),
+		"ring queue":     NewRingQueue(capacity),
+		"priority queue": NewPriorityQueue(capacity, 0),
+		"mpmc queue":     NewMPMCQueue(capacity),
+	}
+}
+
+// Test for checking the behavior shared by every built-in queue.
+func TestQueueImplementations(t *testing.T) {
+	for name, testQueue := range builtinQueues(3) {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, 3, testQueue.Capacity())
+
+			_, err := testQueue.Pop()
+			assert.Equal(t, ErrQueueEmpty, err)
+
+			// wrap around the buffer of the fixed-size queues
+			for round := 0; round < 3; round++ {
+				for i := 0; i < 3; i++ {
+					assert.NoError(t, testQueue.Push(NewJob(func() {}).WithName(strconv.Itoa(i))))
+				}
+				assert.Equal(t, ErrQueueFull, testQueue.Push(NewJob(func() {})))
+				assert.Equal(t, 3, testQueue.Len())
+
+				for i := 0; i < 3; i++ {
+					job, err := testQueue.Pop()
+					assert.NoError(t, err)
+					assert.Equal(t, strconv.Itoa(i), job.Name(), "jobs should be removed in FIFO order.")
+				}
+				assert.Equal(t, 0, testQueue.Len())
+			}
+
+			// closed queues reject new jobs, but can be drained
+			assert.NoError(t, testQueue.Push(NewJob(func() {})))
+			testQueue.Close()
+			assert.Equal(t, ErrQueueClosed, testQueue.Push(NewJob(func() {})))
+
+			_, err = testQueue.Pop()
+			assert.NoError(t, err)
+		})
+	}
+}
+
+// Test for checking queues which cannot hold any job.
+func TestQueueZeroCapacity(t *testing.T) {
+	for name, testQueue := range builtinQueues(0) {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, ErrQueueFull, testQueue.Push(NewJob(func() {})))
+
+			_, err := testQueue.Pop()
+			assert.Equal(t, ErrQueueEmpty, err)
+		})
+	}
+}
+
+// Test for checking that every job pushed to the MPMC queue by concurrent producers is popped exactly once.
+func TestMPMCQueueConcurrent(t *testing.T) {
+	const producers = 4
+	const jobsPerProducer = 500
+
+	testQueue := NewMPMCQueue(64)
+
+	var producing sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		producing.Add(1)
+		go func(p int) {
+			defer producing.Done()
+			for i := 0; i < jobsPerProducer; i++ {
+				job := NewJob(func() {}).WithName(strconv.Itoa(p*jobsPerProducer + i))
+				for testQueue.Push(job) != nil {
+					runtime.Gosched()
+				}
+			}
+		}(p)
+	}
+
+	var mu sync.Mutex
+	seen := make(map[string]int)
+	var consuming sync.WaitGroup
+	done := make(chan struct{})
+	for c := 0; c < producers; c++ {
+		consuming.Add(1)
+		go func() {
+			defer consuming.Done()
+			for {
+				job, err := testQueue.Pop()
+				if err == nil {
+					mu.Lock()
+					seen[job.Name()]++
+					mu.Unlock()
+					continue
+				}
+
+				select {
+				case <-done:
+					if testQueue.Len() == 0 {
+						return
+					}
+				default:
+					runtime.Gosched()
+				}
+			}
+		}()
+	}
+
+	producing.Wait()
+	close(done)
+	consuming.Wait()
+
+	assert.Len(t, seen, producers*jobsPerProducer)
+	for name, count := range seen {
+		assert.Equal(t, 1, count, "job %s should be popped exactly once.", name)
+	}
+}
+
+// Test for checking that the octopus uses the queue provided.
+func TestOctopusWithQueue(t *testing.T) {
+	testOctopus := NewOctopusWithOptions(1, queueCapacity, WithQueue(NewRingQueue(2)))
+
+	release := make(chan struct{})
+	assert.NoError(t, testOctopus.HandleJob(func() { <-release }, "blocker"))
+	assert.NoError(t, testOctopus.HandleJob(func() {}, "job 1"))
+	assert.NoError(t, testOctopus.HandleJob(func() {}, "job 2"))
+	assert.Equal(t, ErrQueueFull, testOctopus.HandleJob(func() {}, "job 3"))
+
+	assert.Equal(t, 2, testOctopus.jobQueue.Len())
+
+	close(release)
+	testOctopus.Wait()
+
+	assert.Equal(t, 0, testOctopus.jobQueue.Len())
+}
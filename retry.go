@@ -0,0 +1,229 @@
+// Copyright 2021 Aadhav Vignesh
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octopool
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// pre-defined backoff multiplier
+const defaultBackoffMultiplier = 2
+
+// RetryPolicy is a struct for representing how failed jobs are retried.
+//
+// A failed job waits for its backoff outside of the pool, so that it does not
+// occupy a worker, and is then handled again like a new job. Panicking jobs and
+// jobs whose context is done are never retried. Once the pool is closed, jobs
+// waiting for a retry fail with their last error right away, so that Shutdown
+// does not wait for their backoff, and ShutdownNow discards them.
+type RetryPolicy struct {
+	MaxAttempts    int                  // maximum number of executions, including the first one
+	InitialBackoff time.Duration        // delay before the first retry
+	MaxBackoff     time.Duration        // upper bound for the delay, zero means no bound
+	Multiplier     float64              // growth of the delay after every retry, defaults to 2
+	Jitter         float64              // fraction of the delay which is randomized, between 0 and 1
+	Retryable      func(err error) bool // reports whether the error is retryable, nil retries every error
+}
+
+// retryTimer is a struct for representing a failed job waiting for its backoff before a retry.
+type retryTimer struct {
+	job   Job   // job to retry
+	err   error // error of the job's last attempt
+	timer Timer // fires once the backoff is over
+}
+
+// retryTimers is a struct for representing the failed jobs waiting for their backoff.
+type retryTimers struct {
+	timers map[*retryTimer]struct{} // jobs waiting for their backoff
+	closed bool                     // reports whether the pool was closed, failed jobs are not retried anymore
+	mu     sync.Mutex               // mutex for locking
+}
+
+// Removes every retry, and stops accepting new ones.
+func (retries *retryTimers) close() map[*retryTimer]struct{} {
+	retries.mu.Lock()
+	defer retries.mu.Unlock()
+
+	timers := retries.timers
+	retries.timers = nil
+	retries.closed = true
+	return timers
+}
+
+// Removes the retry, returns false if it was removed already, in which case the job must not be retried.
+func (retries *retryTimers) take(retry *retryTimer) bool {
+	retries.mu.Lock()
+	defer retries.mu.Unlock()
+
+	if _, ok := retries.timers[retry]; !ok {
+		return false
+	}
+	delete(retries.timers, retry)
+	return true
+}
+
+// Returns the delay before retrying a job which was executed the number of times provided.
+func (policy *RetryPolicy) backoff(attempts int) time.Duration {
+	// without an initial backoff, jobs are retried immediately, however many attempts were made
+	if policy.InitialBackoff <= 0 {
+		return 0
+	}
+
+	multiplier := policy.Multiplier
+	if multiplier <= 0 {
+		multiplier = defaultBackoffMultiplier
+	}
+
+	delay := float64(policy.InitialBackoff) * math.Pow(multiplier, float64(attempts-1))
+	if policy.MaxBackoff > 0 && delay > float64(policy.MaxBackoff) {
+		delay = float64(policy.MaxBackoff)
+	}
+
+	// spread the delay uniformly over [delay - jitter, delay + jitter]
+	if jitter := math.Min(math.Max(policy.Jitter, 0), 1); jitter > 0 {
+		delay += delay * jitter * (2*rand.Float64() - 1)
+	}
+
+	// large delays would overflow to negative durations, and retry immediately
+	if delay >= math.MaxInt64 {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(delay)
+}
+
+// Checks if a job which failed with the error should be retried.
+func (policy *RetryPolicy) shouldRetry(job Job, err error) bool {
+	if job.attempts >= policy.MaxAttempts {
+		return false
+	}
+
+	if errors.Is(err, ErrJobPanicked) || job.isCancelled() {
+		return false
+	}
+
+	return policy.Retryable == nil || policy.Retryable(err)
+}
+
+// Returns the retry policy for the job, nil if the job should not be retried.
+func (octo *Octopus) retryPolicyFor(job Job) *RetryPolicy {
+	if job.retry != nil {
+		return job.retry
+	}
+	return octo.retryPolicy
+}
+
+// Schedules a retry for the failed job if its retry policy allows it, returns false otherwise.
+// The job keeps the context it was submitted with.
+func (octo *Octopus) scheduleRetry(ctx context.Context, job Job, err error) bool {
+	policy := octo.retryPolicyFor(job)
+	if policy == nil || octo.hasFailed() {
+		return false
+	}
+
+	job.ctx = ctx
+	if !policy.shouldRetry(job, err) {
+		return false
+	}
+
+	// the pool does not accept retries once it is closed
+	octo.retries.mu.Lock()
+	defer octo.retries.mu.Unlock()
+	if octo.retries.closed {
+		return false
+	}
+
+	delay := policy.backoff(job.attempts)
+	octo.logger.Info("retrying failed job", "job", job.name, "attempt", job.attempts, "delay", delay, "error", err)
+
+	retry := &retryTimer{job: job, err: err}
+	if octo.retries.timers == nil {
+		octo.retries.timers = make(map[*retryTimer]struct{})
+	}
+	octo.retries.timers[retry] = struct{}{}
+	retry.timer = octo.clock.AfterFunc(delay, func() {
+		// the retry may have been discarded by ShutdownNow
+		if octo.retries.take(retry) {
+			octo.requeue(job, err)
+		}
+	})
+
+	return true
+}
+
+// Hands a job waiting for a retry back to the pool, blocking while the job queue is full.
+// If the pool does not accept the job anymore, the job fails with its last error.
+func (octo *Octopus) requeue(job Job, lastErr error) {
+	for {
+		// take the space channel before trying the pool, so that no signal is missed
+		space := octo.waitForSpace()
+
+		// the pool does not accept retries once it is closed
+		err := ErrInvalidPoolState
+		if !octo.workerPool.isClosed() {
+			err = octo.place(job)
+		}
+		if err == nil {
+			return
+		}
+
+		if errors.Is(err, ErrQueueFull) && octo.blockOn(job.ctx, space) == nil {
+			continue
+		}
+
+		octo.logger.Warn("cannot retry job", "job", job.name, "error", err)
+		octo.abortRetry(job, lastErr)
+		return
+	}
+}
+
+// Fails a job which will not be retried with its last error.
+func (octo *Octopus) abortRetry(job Job, lastErr error) {
+	octo.metrics.finished(lastErr)
+	job.handle.finish(nil, lastErr)
+	octo.recordError(job, lastErr)
+	octo.deadLetter(job, lastErr)
+	octo.workerPool.donePendingJob()
+}
+
+// Stops every retry waiting for its backoff once the pool is closed, the jobs fail with their last error.
+func (octo *Octopus) failRetries() {
+	for retry := range octo.retries.close() {
+		retry.timer.Stop()
+
+		octo.logger.Warn("cannot retry job, pool is closed", "job", retry.job.name, "error", retry.err)
+		octo.abortRetry(retry.job, retry.err)
+	}
+}
+
+// Stops every retry waiting for its backoff, and returns the jobs which will not be retried.
+func (octo *Octopus) discardRetries() []Job {
+	timers := octo.retries.close()
+
+	discarded := make([]Job, 0, len(timers))
+	for retry := range timers {
+		retry.timer.Stop()
+
+		octo.metrics.discarded.Add(1)
+		retry.job.handle.finish(nil, ErrJobDiscarded)
+		octo.workerPool.donePendingJob()
+		discarded = append(discarded, retry.job)
+	}
+	return discarded
+}
@@ -0,0 +1,238 @@
+// Copyright 2021 Aadhav Vignesh
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octopool
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test for checking the exponential backoff without jitter.
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: 10 * time.Millisecond, MaxBackoff: 50 * time.Millisecond}
+
+	assert.Equal(t, 10*time.Millisecond, policy.backoff(1))
+	assert.Equal(t, 20*time.Millisecond, policy.backoff(2))
+	assert.Equal(t, 40*time.Millisecond, policy.backoff(3))
+	assert.Equal(t, 50*time.Millisecond, policy.backoff(4), "backoff should be capped.")
+
+	policy.Multiplier = 3
+	assert.Equal(t, 30*time.Millisecond, policy.backoff(2))
+
+	// large attempts must not overflow to negative delays
+	policy = RetryPolicy{InitialBackoff: time.Second}
+	assert.Equal(t, time.Duration(math.MaxInt64), policy.backoff(35))
+	assert.Equal(t, time.Duration(math.MaxInt64), policy.backoff(5000))
+	policy.Jitter = 1
+	assert.Greater(t, policy.backoff(100), time.Duration(0))
+
+	policy = RetryPolicy{InitialBackoff: time.Second, MaxBackoff: time.Minute}
+	assert.Equal(t, time.Minute, policy.backoff(100))
+
+	policy = RetryPolicy{}
+	assert.Equal(t, time.Duration(0), policy.backoff(5000))
+}
+
+// Test for checking that the jitter stays within its bounds.
+func TestRetryPolicyJitter(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: 100 * time.Millisecond, Jitter: 0.5}
+
+	for i := 0; i < 100; i++ {
+		delay := policy.backoff(1)
+		assert.GreaterOrEqual(t, delay, 50*time.Millisecond)
+		assert.LessOrEqual(t, delay, 150*time.Millisecond)
+	}
+}
+
+// Test for checking that a failing job is retried until it succeeds.
+func TestRetrySuccess(t *testing.T) {
+	testOctopus := NewOctopusWithOptions(1, queueCapacity, WithRetryPolicy(RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+	}))
+
+	var calls int32
+	handle, err := testOctopus.Submit(func() (interface{}, error) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			return nil, errors.New("flaky")
+		}
+		return "done", nil
+	}, "flaky")
+	assert.NoError(t, err)
+
+	result, err := handle.Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "done", result)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+	assert.NoError(t, testOctopus.WaitErr(), "failed attempts which were retried should not be reported.")
+}
+
+// Test for checking that a job fails with its last error once its attempts are exhausted.
+func TestRetryExhausted(t *testing.T) {
+	testOctopus := NewOctopusWithOptions(1, queueCapacity, WithRetryPolicy(RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+	}))
+	errFlaky := errors.New("flaky")
+
+	var calls int32
+	assert.NoError(t, testOctopus.HandleJobErr(func() error {
+		atomic.AddInt32(&calls, 1)
+		return errFlaky
+	}, "flaky"))
+
+	err := testOctopus.WaitErr()
+
+	var jobErr *JobError
+	assert.True(t, errors.As(err, &jobErr))
+	assert.True(t, errors.Is(err, errFlaky))
+	assert.Equal(t, 3, jobErr.Attempts)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+// Test for checking that only retryable errors are retried, and that jobs can override the policy.
+func TestRetryRetryable(t *testing.T) {
+	errPermanent := errors.New("permanent")
+	testOctopus := NewOctopusWithOptions(1, queueCapacity, WithRetryPolicy(RetryPolicy{
+		MaxAttempts: 5,
+		Retryable: func(err error) bool {
+			return !errors.Is(err, errPermanent)
+		},
+	}))
+
+	var permanentCalls, overriddenCalls int32
+	assert.NoError(t, testOctopus.HandleJobErr(func() error {
+		atomic.AddInt32(&permanentCalls, 1)
+		return errPermanent
+	}, "permanent"))
+	assert.True(t, testOctopus.TrySubmit(NewJobErr(func() error {
+		atomic.AddInt32(&overriddenCalls, 1)
+		return errors.New("transient")
+	}).WithName("overridden").WithRetry(RetryPolicy{MaxAttempts: 2})))
+
+	assert.Error(t, testOctopus.WaitErr())
+	assert.Equal(t, int32(1), atomic.LoadInt32(&permanentCalls))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&overriddenCalls))
+}
+
+// Test for checking that a job waiting for a retry does not occupy a worker.
+func TestRetryFreesWorker(t *testing.T) {
+	testOctopus := NewOctopusWithOptions(1, queueCapacity, WithRetryPolicy(RetryPolicy{
+		MaxAttempts:    2,
+		InitialBackoff: 100 * time.Millisecond,
+	}))
+
+	var mu sync.Mutex
+	var order []string
+	record := func(name string) {
+		mu.Lock()
+		order = append(order, name)
+		mu.Unlock()
+	}
+
+	var calls int32
+	assert.NoError(t, testOctopus.HandleJobErr(func() error {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			record("failing")
+			return errors.New("flaky")
+		}
+		record("retried")
+		return nil
+	}, "flaky"))
+	assert.NoError(t, testOctopus.HandleJob(func() { record("queued") }, "queued"))
+
+	testOctopus.Wait()
+
+	assert.Equal(t, []string{"failing", "queued", "retried"}, order)
+}
+
+// Returns the number of jobs waiting for a retry.
+func pendingRetries(octo *Octopus) int {
+	octo.retries.mu.Lock()
+	defer octo.retries.mu.Unlock()
+
+	return len(octo.retries.timers)
+}
+
+// Returns an octopus with a fake clock, and a job which always fails and has waited for a retry once it is returned.
+func newRetryingOctopus(t *testing.T) (*Octopus, *FakeClock, *JobHandle, *int32) {
+	clock := NewFakeClock(fakeStart)
+	testOctopus := NewOctopusWithOptions(1, queueCapacity, WithClock(clock), WithRetryPolicy(RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 50 * time.Millisecond,
+	}))
+
+	var calls int32
+	handle, err := testOctopus.Submit(func() (interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		return nil, errors.New("octopus down")
+	}, "failing")
+	assert.NoError(t, err)
+
+	assert.Eventually(t, func() bool { return pendingRetries(testOctopus) == 1 }, time.Second, time.Millisecond)
+	return testOctopus, clock, handle, &calls
+}
+
+// Test for checking that ShutdownNow discards the jobs waiting for a retry.
+func TestRetryShutdownNow(t *testing.T) {
+	testOctopus, clock, handle, calls := newRetryingOctopus(t)
+
+	discarded := testOctopus.ShutdownNow()
+	assert.Len(t, discarded, 1)
+	assert.Equal(t, "failing", discarded[0].Name())
+	assert.Equal(t, ErrJobDiscarded, handle.Err())
+
+	clock.Advance(time.Second)
+	testOctopus.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(calls))
+	assert.Equal(t, 0, pendingRetries(testOctopus))
+	assert.Equal(t, uint64(1), testOctopus.Stats().Discarded)
+}
+
+// Test for checking that jobs waiting for a retry fail with their last error as soon as the pool is closed.
+func TestRetryClosedPool(t *testing.T) {
+	testOctopus, clock, handle, calls := newRetryingOctopus(t)
+
+	testOctopus.Close()
+	testOctopus.Wait()
+
+	assert.EqualError(t, handle.Err(), "octopus down")
+	assert.Equal(t, 0, pendingRetries(testOctopus))
+
+	clock.Advance(time.Second)
+	assert.Equal(t, int32(1), atomic.LoadInt32(calls))
+}
+
+// Test for checking that Shutdown does not wait for the backoff of jobs waiting for a retry.
+func TestRetryShutdown(t *testing.T) {
+	testOctopus, _, handle, calls := newRetryingOctopus(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, testOctopus.Shutdown(ctx))
+
+	assert.EqualError(t, handle.Err(), "octopus down")
+	assert.Equal(t, int32(1), atomic.LoadInt32(calls))
+	assert.Equal(t, uint64(1), testOctopus.Stats().Failed)
+}
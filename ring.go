@@ -0,0 +1,94 @@
+// Copyright 2021 Aadhav Vignesh
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octopool
+
+import "sync"
+
+// RingQueue is a struct for representing a FIFO queue which holds pending jobs in a fixed-size ring buffer.
+// Unlike JobQueue, removing a job never moves the remaining jobs. It is safe for concurrent use.
+type RingQueue struct {
+	jobs   []Job      // ring buffer of jobs
+	head   int        // index of the next job to remove
+	count  int        // total jobs present in the queue
+	closed bool       // represents if the queue accepts new jobs
+	mu     sync.Mutex // mutex for locking
+}
+
+// NewRingQueue returns a ring queue with the specified capacity.
+func NewRingQueue(queueCapacity int) *RingQueue {
+	if queueCapacity < 0 {
+		queueCapacity = 0
+	}
+
+	ringQueue := RingQueue{jobs: make([]Job, queueCapacity)}
+	return &ringQueue
+}
+
+// Push adds a job to the queue.
+func (ringQueue *RingQueue) Push(job Job) error {
+	ringQueue.mu.Lock()
+	defer ringQueue.mu.Unlock()
+
+	if ringQueue.closed {
+		return ErrQueueClosed
+	}
+
+	if ringQueue.count >= len(ringQueue.jobs) {
+		return ErrQueueFull
+	}
+
+	ringQueue.jobs[(ringQueue.head+ringQueue.count)%len(ringQueue.jobs)] = job
+	ringQueue.count++
+	return nil
+}
+
+// Pop removes the oldest job from the queue.
+func (ringQueue *RingQueue) Pop() (Job, error) {
+	ringQueue.mu.Lock()
+	defer ringQueue.mu.Unlock()
+
+	if ringQueue.count == 0 {
+		return Job{}, ErrQueueEmpty
+	}
+
+	job := ringQueue.jobs[ringQueue.head]
+
+	// clear the slot so that the job can be garbage collected
+	ringQueue.jobs[ringQueue.head] = Job{}
+	ringQueue.head = (ringQueue.head + 1) % len(ringQueue.jobs)
+	ringQueue.count--
+	return job, nil
+}
+
+// Len returns the number of jobs in the queue.
+func (ringQueue *RingQueue) Len() int {
+	ringQueue.mu.Lock()
+	defer ringQueue.mu.Unlock()
+
+	return ringQueue.count
+}
+
+// Capacity returns the queue's capacity.
+func (ringQueue *RingQueue) Capacity() int {
+	return len(ringQueue.jobs)
+}
+
+// Close stops the queue from accepting new jobs.
+func (ringQueue *RingQueue) Close() {
+	ringQueue.mu.Lock()
+	defer ringQueue.mu.Unlock()
+
+	ringQueue.closed = true
+}
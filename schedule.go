@@ -0,0 +1,250 @@
+// Copyright 2021 Aadhav Vignesh
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octopool
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+)
+
+// ScheduledJob is a struct for representing a job which runs at a specific time.
+// It embeds the job's handle, which can be used to wait for the job and collect its outcome.
+type ScheduledJob struct {
+	*JobHandle
+	entry   *scheduledJob // entry of the job in the schedule
+	octopus *Octopus      // octopus which runs the job
+}
+
+// At returns the time at which the job is due.
+func (scheduled *ScheduledJob) At() time.Time {
+	return scheduled.entry.at
+}
+
+// Cancel removes the job from the schedule, returns false if the job was already due or cancelled.
+// The job's handle receives ErrJobUnscheduled.
+func (scheduled *ScheduledJob) Cancel() bool {
+	if !scheduled.octopus.unschedule(scheduled.entry) {
+		return false
+	}
+
+	scheduled.octopus.logger.Info("cancelled scheduled job", "job", scheduled.entry.job.name)
+	scheduled.octopus.metrics.cancelled.Add(1)
+	scheduled.entry.job.handle.finish(nil, ErrJobUnscheduled)
+	scheduled.octopus.workerPool.donePendingJob()
+	return true
+}
+
+// scheduledJob is a struct for representing a job waiting in the schedule.
+type scheduledJob struct {
+	job      Job       // the job to run
+	at       time.Time // time at which the job is due
+	sequence uint64    // order in which the job was scheduled, keeps FIFO order between equal times
+	index    int       // index of the job in the heap, -1 once removed
+}
+
+// scheduleHeap is a struct for representing scheduled jobs ordered by the time they are due.
+type scheduleHeap []*scheduledJob
+
+// Len returns the number of scheduled jobs.
+func (h scheduleHeap) Len() int {
+	return len(h)
+}
+
+// Less orders jobs by the time they are due.
+func (h scheduleHeap) Less(i, j int) bool {
+	if h[i].at.Equal(h[j].at) {
+		return h[i].sequence < h[j].sequence
+	}
+	return h[i].at.Before(h[j].at)
+}
+
+// Swap swaps two jobs.
+func (h scheduleHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+// Push adds a job, used by container/heap.
+func (h *scheduleHeap) Push(x interface{}) {
+	entry := x.(*scheduledJob)
+	entry.index = len(*h)
+	*h = append(*h, entry)
+}
+
+// Pop removes the last job, used by container/heap.
+func (h *scheduleHeap) Pop() interface{} {
+	old := *h
+	last := len(old) - 1
+	entry := old[last]
+	old[last] = nil
+	entry.index = -1
+	*h = old[:last]
+	return entry
+}
+
+// scheduler is a struct for representing the jobs which are not due yet.
+type scheduler struct {
+	jobs     scheduleHeap // jobs ordered by the time they are due
+	sequence uint64       // sequence for the next job
+	timer    Timer        // fires once the earliest job is due
+	mu       sync.Mutex   // mutex for locking
+}
+
+// HandleJobAt handles a job once the time provided is reached, a time in the past handles the job right away.
+// The job counts as pending, so Wait blocks until it has run, and it can be cancelled until it is due.
+func (octo *Octopus) HandleJobAt(at time.Time, fun func(), name ...string) (*ScheduledJob, error) {
+	// throw error if pool is closed
+	if octo.workerPool.isClosed() {
+		return nil, ErrInvalidPoolState
+	}
+
+	// throw error if function provided is invalid
+	if fun == nil {
+		return nil, ErrNilFunction
+	}
+
+	// create a job with a handle
+	job := Job{function: fun, name: jobName(name)}
+	job.handle = newJobHandle(job.name)
+
+	return octo.schedule(at, job), nil
+}
+
+// HandleJobAfter handles a job once the delay provided has passed.
+// The job counts as pending, so Wait blocks until it has run, and it can be cancelled until it is due.
+func (octo *Octopus) HandleJobAfter(delay time.Duration, fun func(), name ...string) (*ScheduledJob, error) {
+	return octo.HandleJobAt(octo.clock.Now().Add(delay), fun, name...)
+}
+
+// ScheduledJobs returns the number of jobs which are not due yet.
+func (octo *Octopus) ScheduledJobs() int {
+	octo.scheduled.mu.Lock()
+	defer octo.scheduled.mu.Unlock()
+
+	return octo.scheduled.jobs.Len()
+}
+
+// Adds the job to the schedule, the job is pending until it has run or is cancelled.
+func (octo *Octopus) schedule(at time.Time, job Job) *ScheduledJob {
+	octo.workerPool.addPendingJob()
+	octo.metrics.submitted.Add(1)
+	job.scheduled = true
+
+	octo.scheduled.mu.Lock()
+	defer octo.scheduled.mu.Unlock()
+
+	octo.scheduled.sequence++
+	entry := &scheduledJob{job: job, at: at, sequence: octo.scheduled.sequence}
+	heap.Push(&octo.scheduled.jobs, entry)
+
+	if octo.debugEnabled() {
+		octo.logger.Debug("scheduled job", "job", job.name, "at", at)
+	}
+
+	// the job is the earliest one, the timer must fire earlier
+	if entry.index == 0 {
+		octo.armSchedule()
+	}
+
+	return &ScheduledJob{JobHandle: job.handle, entry: entry, octopus: octo}
+}
+
+// Removes the job from the schedule, returns false if it is not scheduled anymore.
+func (octo *Octopus) unschedule(entry *scheduledJob) bool {
+	octo.scheduled.mu.Lock()
+	defer octo.scheduled.mu.Unlock()
+
+	if entry.index < 0 {
+		return false
+	}
+
+	heap.Remove(&octo.scheduled.jobs, entry.index)
+	return true
+}
+
+// Sets the timer to fire once the earliest job is due, the schedule's lock must be held.
+func (octo *Octopus) armSchedule() {
+	if octo.scheduled.jobs.Len() == 0 {
+		return
+	}
+
+	if octo.scheduled.timer != nil {
+		octo.scheduled.timer.Stop()
+	}
+
+	delay := octo.scheduled.jobs[0].at.Sub(octo.clock.Now())
+	octo.scheduled.timer = octo.clock.AfterFunc(delay, octo.releaseDueJobs)
+}
+
+// Hands the jobs which are due to the pool, and sets the timer for the next job.
+func (octo *Octopus) releaseDueJobs() {
+	for {
+		octo.scheduled.mu.Lock()
+		if octo.scheduled.jobs.Len() == 0 || octo.scheduled.jobs[0].at.After(octo.clock.Now()) {
+			octo.armSchedule()
+			octo.scheduled.mu.Unlock()
+			return
+		}
+		entry := heap.Pop(&octo.scheduled.jobs).(*scheduledJob)
+		octo.scheduled.mu.Unlock()
+
+		octo.releaseScheduledJob(entry.job)
+	}
+}
+
+// Hands a job which is due to the pool, the job's pending count moves from the schedule to the pool.
+func (octo *Octopus) releaseScheduledJob(job Job) {
+	defer octo.workerPool.donePendingJob()
+
+	if octo.debugEnabled() {
+		octo.logger.Debug("scheduled job is due", "job", job.name)
+	}
+
+	// the pool may have been closed while the job was due
+	err := ErrInvalidPoolState
+	if !octo.workerPool.isClosed() {
+		err = octo.dispatch(job)
+	}
+	if err != nil {
+		octo.logger.Warn("cannot handle scheduled job", "job", job.name, "error", err)
+		octo.metrics.discarded.Add(1)
+		job.handle.finish(nil, err)
+	}
+}
+
+// Removes every job from the schedule, and returns the jobs which never ran.
+func (octo *Octopus) discardScheduledJobs() []Job {
+	octo.scheduled.mu.Lock()
+	entries := octo.scheduled.jobs
+	octo.scheduled.jobs = nil
+	for _, entry := range entries {
+		entry.index = -1
+	}
+	if octo.scheduled.timer != nil {
+		octo.scheduled.timer.Stop()
+	}
+	octo.scheduled.mu.Unlock()
+
+	discarded := make([]Job, 0, len(entries))
+	for _, entry := range entries {
+		octo.metrics.discarded.Add(1)
+		entry.job.handle.finish(nil, ErrJobDiscarded)
+		octo.workerPool.donePendingJob()
+		discarded = append(discarded, entry.job)
+	}
+	return discarded
+}
@@ -0,0 +1,116 @@
+// Copyright 2021 Aadhav Vignesh
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octopool
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test for checking that Wait blocks until delayed jobs have run.
+func TestHandleJobAfter(t *testing.T) {
+	testOctopus := NewOctopus(1, queueCapacity)
+
+	var ranAt time.Time
+	start := time.Now()
+	scheduled, err := testOctopus.HandleJobAfter(30*time.Millisecond, func() { ranAt = time.Now() }, "delayed")
+	assert.NoError(t, err)
+	assert.Equal(t, "delayed", scheduled.Name())
+	assert.Equal(t, 1, testOctopus.ScheduledJobs())
+
+	testOctopus.Wait()
+
+	assert.GreaterOrEqual(t, ranAt.Sub(start), 30*time.Millisecond)
+	assert.False(t, ranAt.Before(scheduled.At()))
+	assert.NoError(t, scheduled.Err())
+	assert.Equal(t, 0, testOctopus.ScheduledJobs())
+}
+
+// Test for checking that scheduled jobs run in the order they are due.
+func TestHandleJobAtOrder(t *testing.T) {
+	testOctopus := NewOctopus(1, queueCapacity)
+	now := time.Now()
+
+	var mu sync.Mutex
+	var order []string
+	for _, job := range []struct {
+		name  string
+		delay time.Duration
+	}{{"third", 60 * time.Millisecond}, {"first", 20 * time.Millisecond}, {"second", 40 * time.Millisecond}, {"past", -time.Second}} {
+		name := job.name
+		_, err := testOctopus.HandleJobAt(now.Add(job.delay), func() {
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+		}, name)
+		assert.NoError(t, err)
+	}
+
+	testOctopus.Wait()
+
+	assert.Equal(t, []string{"past", "first", "second", "third"}, order)
+}
+
+// Test for checking that scheduled jobs can be cancelled before they are due.
+func TestScheduledJobCancel(t *testing.T) {
+	testOctopus := NewOctopus(1, queueCapacity)
+
+	ran := false
+	scheduled, err := testOctopus.HandleJobAfter(time.Hour, func() { ran = true }, "cancelled")
+	assert.NoError(t, err)
+
+	assert.True(t, scheduled.Cancel())
+	assert.False(t, scheduled.Cancel(), "a job should only be cancelled once.")
+
+	testOctopus.Wait()
+
+	assert.False(t, ran)
+	assert.ErrorIs(t, scheduled.Err(), ErrJobUnscheduled)
+	assert.Equal(t, 0, testOctopus.ScheduledJobs())
+
+	// jobs which already ran cannot be cancelled
+	scheduled, err = testOctopus.HandleJobAfter(0, func() {}, "due")
+	assert.NoError(t, err)
+	testOctopus.Wait()
+	assert.False(t, scheduled.Cancel())
+	assert.NoError(t, scheduled.Err())
+}
+
+// Test for checking that scheduled jobs are discarded when the octopus is closed.
+func TestScheduledJobsDiscarded(t *testing.T) {
+	testOctopus := NewOctopus(1, queueCapacity)
+
+	closed, err := testOctopus.HandleJobAfter(time.Hour, func() {}, "closed")
+	assert.NoError(t, err)
+	testOctopus.Close()
+	testOctopus.Wait()
+
+	assert.ErrorIs(t, closed.Err(), ErrJobDiscarded)
+	_, err = testOctopus.HandleJobAfter(time.Hour, func() {}, "rejected")
+	assert.ErrorIs(t, err, ErrInvalidPoolState)
+
+	// ShutdownNow reports the scheduled jobs which never ran
+	testOctopus = NewOctopus(1, queueCapacity)
+	_, err = testOctopus.HandleJobAfter(time.Hour, func() {}, "shutdown")
+	assert.NoError(t, err)
+
+	discarded := testOctopus.ShutdownNow()
+	if assert.Len(t, discarded, 1) {
+		assert.Equal(t, "shutdown", discarded[0].Name())
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2021 Aadhav Vignesh
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octopool
+
+import (
+	"context"
+	"fmt"
+)
+
+// ShutdownError is the error returned by Shutdown when the context is done before every job has finished.
+type ShutdownError struct {
+	Err         error // error of the context passed to Shutdown
+	QueuedJobs  int   // number of jobs still waiting in the job queue
+	RunningJobs int   // number of jobs still being executed by workers
+}
+
+// Formats ShutdownError struct.
+func (err *ShutdownError) Error() string {
+	return fmt.Sprintf("shutdown: %v: %d queued and %d running jobs unfinished", err.Err, err.QueuedJobs, err.RunningJobs)
+}
+
+// Unwrap returns the context's error.
+func (err *ShutdownError) Unwrap() error {
+	return err.Err
+}
+
+// Shutdown closes the worker pool, and waits until the job queue is drained and every running job has finished.
+// If ctx is done first, it returns a *ShutdownError wrapping ctx's error, and the remaining jobs keep running.
+func (octo *Octopus) Shutdown(ctx context.Context) error {
+	octo.logger.Info("shutting down, waiting for jobs to finish",
+		"pending", octo.workerPool.getPendingJobsCount(), "queued", octo.jobQueue.Len())
+	octo.Close()
+
+	select {
+	case <-octo.workerPool.idleSignal():
+		return nil
+	case <-ctx.Done():
+		queued := octo.jobQueue.Len()
+		running := octo.workerPool.getPendingJobsCount() - queued
+		if running < 0 {
+			running = 0
+		}
+
+		return &ShutdownError{
+			Err:         ctx.Err(),
+			QueuedJobs:  queued,
+			RunningJobs: running,
+		}
+	}
+}
+
+// ShutdownNow closes the worker pool and discards the job queue, the scheduled jobs and the jobs waiting for a retry,
+// returning the jobs which never ran or will not run again.
+// Running jobs are not interrupted, Wait can be used to wait for them.
+func (octo *Octopus) ShutdownNow() []Job {
+	octo.logger.Info("shutting down, discarding queued jobs", "queued", octo.jobQueue.Len())
+	// discard the retries before Close fails them
+	discarded := octo.discardScheduledJobs()
+	discarded = append(discarded, octo.discardRetries()...)
+	octo.Close()
+
+	for {
+		job, err := octo.jobQueue.Pop()
+		if err != nil {
+			break
+		}
+
+		octo.metrics.discarded.Add(1)
+		job.traceEnd(ErrJobDiscarded)
+		job.handle.finish(nil, ErrJobDiscarded)
+		octo.workerPool.donePendingJob()
+		discarded = append(discarded, job)
+	}
+
+	return discarded
+}
@@ -0,0 +1,108 @@
+// Copyright 2021 Aadhav Vignesh
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octopool
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test for checking that Shutdown drains the job queue before returning.
+func TestShutdown(t *testing.T) {
+	testOctopus := NewOctopus(1)
+
+	var ran int32
+	for i := 0; i < 3; i++ {
+		err := testOctopus.HandleJob(func() {
+			time.Sleep(10 * time.Millisecond)
+			atomic.AddInt32(&ran, 1)
+		}, "sleepy")
+		if err != nil {
+			t.Errorf("Got error while handling job: %v", err)
+		}
+	}
+
+	err := testOctopus.Shutdown(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&ran), "queued jobs should run before shutdown returns.")
+	assert.Equal(t, ErrInvalidPoolState, testOctopus.HandleJob(func() {}, "late"))
+}
+
+// Test for checking that Shutdown reports the unfinished jobs when the deadline is hit.
+func TestShutdownDeadline(t *testing.T) {
+	testOctopus := NewOctopus(1)
+
+	release := make(chan struct{})
+	defer close(release)
+
+	for i := 0; i < 3; i++ {
+		err := testOctopus.HandleJob(func() { <-release }, "blocked")
+		if err != nil {
+			t.Errorf("Got error while handling job: %v", err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := testOctopus.Shutdown(ctx)
+
+	var shutdownErr *ShutdownError
+	assert.True(t, errors.As(err, &shutdownErr))
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Equal(t, 2, shutdownErr.QueuedJobs)
+	assert.Equal(t, 1, shutdownErr.RunningJobs)
+}
+
+// Test for checking that ShutdownNow discards the job queue and returns the jobs which never ran.
+func TestShutdownNow(t *testing.T) {
+	testOctopus := NewOctopus(1)
+
+	release := make(chan struct{})
+	err := testOctopus.HandleJob(func() { <-release }, "running")
+	if err != nil {
+		t.Errorf("Got error while handling job: %v", err)
+	}
+
+	handle, err := testOctopus.Submit(func() (interface{}, error) { return nil, nil }, "queued 1")
+	if err != nil {
+		t.Errorf("Got error while submitting job: %v", err)
+	}
+	err = testOctopus.HandleJob(func() {}, "queued 2")
+	if err != nil {
+		t.Errorf("Got error while handling job: %v", err)
+	}
+
+	discarded := testOctopus.ShutdownNow()
+
+	var names []string
+	for _, job := range discarded {
+		names = append(names, job.Name())
+	}
+	assert.Equal(t, []string{"queued 1", "queued 2"}, names)
+	assert.Equal(t, ErrJobDiscarded, handle.Err())
+	assert.Equal(t, 0, testOctopus.jobQueue.Len())
+
+	// the running job is not interrupted
+	close(release)
+	testOctopus.Wait()
+	assert.Equal(t, 1, testOctopus.AvailableWorkers())
+}
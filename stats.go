@@ -0,0 +1,248 @@
+// Copyright 2021 Aadhav Vignesh
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octopool
+
+import (
+	"math"
+	"math/bits"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// maxStatsNames is the number of job names whose latencies are tracked, jobs with further names are not tracked.
+const maxStatsNames = 1024
+
+// Constants for the buckets of latency histograms, every power of two of nanoseconds is split into linear buckets.
+const (
+	subBucketBits  = 3                                     // number of bits of a duration which select its linear bucket
+	subBuckets     = 1 << subBucketBits                    // number of linear buckets per power of two
+	latencyBuckets = (63 - subBucketBits + 1) * subBuckets // number of buckets covering every positive duration
+)
+
+// Stats is a struct for representing an immutable snapshot of the statistics of an octopus.
+type Stats struct {
+	Submitted         uint64              // number of jobs accepted by the octopus, every one of them ends with one outcome below
+	Completed         uint64              // number of jobs which succeeded
+	Failed            uint64              // number of jobs which failed with an error, after their retries
+	Panicked          uint64              // number of jobs which panicked
+	Dropped           uint64              // number of jobs dropped by the overflow policy
+	Cancelled         uint64              // number of jobs skipped as their context was done or the pool failed fast, or unscheduled
+	Discarded         uint64              // number of jobs discarded without running when the pool was closed or shut down
+	TimedOut          int64               // number of jobs which outlived their timeout
+	Abandoned         int                 // number of abandoned jobs which are still running
+	QueueLength       int                 // number of jobs in the job queue
+	PeakQueueLength   int                 // highest number of jobs in the job queue
+	ActiveWorkers     int                 // number of active workers
+	PeakActiveWorkers int                 // highest number of active workers
+	Jobs              map[string]JobStats // latencies per job name
+}
+
+// JobStats is a struct for representing the latencies of the jobs sharing a name.
+type JobStats struct {
+	Runs uint64      // number of executions, retries included
+	Wait Percentiles // time between accepting a job and its first execution
+	Run  Percentiles // time spent executing the job, for every attempt
+}
+
+// Percentiles is a struct for representing the percentiles of a latency distribution.
+// Percentiles are estimated from histograms, with an error of at most one sixteenth of the value.
+type Percentiles struct {
+	P50 time.Duration // median
+	P90 time.Duration // 90th percentile
+	P99 time.Duration // 99th percentile
+}
+
+// latencyHistogram is a struct for representing a histogram of durations with log-linear buckets, safe for concurrent use.
+type latencyHistogram struct {
+	counts [latencyBuckets]atomic.Uint64 // number of observations per bucket
+	count  atomic.Uint64                 // total number of observations
+}
+
+// Returns the bucket of a duration.
+func latencyBucket(d time.Duration) int {
+	if d < subBuckets {
+		return int(max(d, 0))
+	}
+
+	exponent := bits.Len64(uint64(d)) - 1
+	mantissa := int(uint64(d)>>(exponent-subBucketBits)) & (subBuckets - 1)
+	return (exponent-subBucketBits+1)*subBuckets + mantissa
+}
+
+// Returns the middle of the durations falling into the bucket.
+func latencyBucketValue(bucket int) time.Duration {
+	if bucket < subBuckets {
+		return time.Duration(bucket)
+	}
+
+	exponent := bucket/subBuckets + subBucketBits - 1
+	mantissa := bucket % subBuckets
+	lower := float64(subBuckets+mantissa) * math.Exp2(float64(exponent-subBucketBits))
+	width := math.Exp2(float64(exponent - subBucketBits))
+	return time.Duration(lower + width/2)
+}
+
+// Records a duration.
+func (h *latencyHistogram) observe(d time.Duration) {
+	h.counts[latencyBucket(d)].Add(1)
+	h.count.Add(1)
+}
+
+// Returns the estimated median, 90th and 99th percentiles.
+func (h *latencyHistogram) percentiles() Percentiles {
+	var counts [latencyBuckets]uint64
+	var total uint64
+	for i := range h.counts {
+		counts[i] = h.counts[i].Load()
+		total += counts[i]
+	}
+
+	return Percentiles{
+		P50: quantile(&counts, total, 0.50),
+		P90: quantile(&counts, total, 0.90),
+		P99: quantile(&counts, total, 0.99),
+	}
+}
+
+// Returns the estimated quantile of the bucket counts, zero if there are no observations.
+func quantile(counts *[latencyBuckets]uint64, total uint64, q float64) time.Duration {
+	if total == 0 {
+		return 0
+	}
+
+	rank := uint64(math.Ceil(q * float64(total)))
+	var cumulative uint64
+	for bucket, count := range counts {
+		cumulative += count
+		if cumulative >= rank {
+			return latencyBucketValue(bucket)
+		}
+	}
+	return latencyBucketValue(latencyBuckets - 1)
+}
+
+// jobLatencies is a struct for representing the latencies of the jobs sharing a name.
+type jobLatencies struct {
+	wait latencyHistogram // time between accepting a job and its first execution
+	run  latencyHistogram // time spent executing the job
+}
+
+// poolStats is a struct for representing statistics which are updated with atomics, so that reading them never locks the pool.
+type poolStats struct {
+	activeWorkers atomic.Int64             // number of active workers, mirrors the pool's count
+	peakWorkers   atomic.Int64             // highest number of active workers
+	peakQueued    atomic.Int64             // highest number of jobs in the job queue
+	jobs          map[string]*jobLatencies // latencies per job name
+	jobsMu        sync.RWMutex             // mutex for locking the latencies per job name
+}
+
+// Records the number of active workers, the pool's lock must be held.
+func (s *poolStats) setActiveWorkers(active int) {
+	s.activeWorkers.Store(int64(active))
+	if int64(active) > s.peakWorkers.Load() {
+		s.peakWorkers.Store(int64(active))
+	}
+}
+
+// Records the number of jobs in the job queue.
+func (s *poolStats) observeQueued(queued int) {
+	for {
+		peak := s.peakQueued.Load()
+		if int64(queued) <= peak || s.peakQueued.CompareAndSwap(peak, int64(queued)) {
+			return
+		}
+	}
+}
+
+// Returns the latencies of the job name, nil if too many names are tracked already.
+func (s *poolStats) latencies(name string) *jobLatencies {
+	s.jobsMu.RLock()
+	latencies, ok := s.jobs[name]
+	s.jobsMu.RUnlock()
+	if ok {
+		return latencies
+	}
+
+	s.jobsMu.Lock()
+	defer s.jobsMu.Unlock()
+
+	if latencies, ok := s.jobs[name]; ok {
+		return latencies
+	}
+	if len(s.jobs) >= maxStatsNames {
+		return nil
+	}
+	if s.jobs == nil {
+		s.jobs = make(map[string]*jobLatencies)
+	}
+
+	latencies = &jobLatencies{}
+	s.jobs[name] = latencies
+	return latencies
+}
+
+// Records the time a job waited before its first execution.
+func (s *poolStats) observeWait(name string, d time.Duration) {
+	if latencies := s.latencies(name); latencies != nil {
+		latencies.wait.observe(d)
+	}
+}
+
+// Records the time spent executing a job.
+func (s *poolStats) observeRun(name string, d time.Duration) {
+	if latencies := s.latencies(name); latencies != nil {
+		latencies.run.observe(d)
+	}
+}
+
+// Returns the latencies of every tracked job name.
+func (s *poolStats) jobStats() map[string]JobStats {
+	s.jobsMu.RLock()
+	defer s.jobsMu.RUnlock()
+
+	jobs := make(map[string]JobStats, len(s.jobs))
+	for name, latencies := range s.jobs {
+		jobs[name] = JobStats{
+			Runs: latencies.run.count.Load(),
+			Wait: latencies.wait.percentiles(),
+			Run:  latencies.run.percentiles(),
+		}
+	}
+	return jobs
+}
+
+// Stats returns an immutable snapshot of the octopus' statistics.
+// Reading the statistics never locks the pool, so it does not slow down the assignment of jobs.
+func (octo *Octopus) Stats() Stats {
+	stats := &octo.workerPool.stats
+
+	return Stats{
+		Submitted:         octo.metrics.submitted.Load(),
+		Completed:         octo.metrics.completed.Load(),
+		Failed:            octo.metrics.failed.Load(),
+		Panicked:          octo.metrics.panicked.Load(),
+		Dropped:           octo.metrics.dropped.Load(),
+		Cancelled:         octo.metrics.cancelled.Load(),
+		Discarded:         octo.metrics.discarded.Load(),
+		TimedOut:          octo.TimedOutJobs(),
+		Abandoned:         octo.AbandonedJobs(),
+		QueueLength:       octo.jobQueue.Len(),
+		PeakQueueLength:   int(stats.peakQueued.Load()),
+		ActiveWorkers:     int(stats.activeWorkers.Load()),
+		PeakActiveWorkers: int(stats.peakWorkers.Load()),
+		Jobs:              stats.jobStats(),
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2021 Aadhav Vignesh
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octopool
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test for checking that durations fall into buckets whose value is close to them.
+func TestLatencyBucket(t *testing.T) {
+	for _, d := range []time.Duration{0, 1, 7, 8, 15, 16, 1000, time.Millisecond, 3 * time.Second, time.Hour, 1<<63 - 1} {
+		value := latencyBucketValue(latencyBucket(d))
+		assert.InDelta(t, float64(d), float64(value), float64(d)/16+1, "duration %d", d)
+	}
+
+	assert.Equal(t, 0, latencyBucket(-time.Second))
+	assert.Equal(t, latencyBuckets-1, latencyBucket(1<<63-1))
+}
+
+// Test for checking the percentiles of a latency histogram.
+func TestLatencyPercentiles(t *testing.T) {
+	var h latencyHistogram
+	assert.Equal(t, Percentiles{}, h.percentiles())
+
+	for i := 1; i <= 100; i++ {
+		h.observe(time.Duration(i) * time.Millisecond)
+	}
+
+	percentiles := h.percentiles()
+	assert.InDelta(t, float64(50*time.Millisecond), float64(percentiles.P50), float64(50*time.Millisecond)/16)
+	assert.InDelta(t, float64(90*time.Millisecond), float64(percentiles.P90), float64(90*time.Millisecond)/16)
+	assert.InDelta(t, float64(99*time.Millisecond), float64(percentiles.P99), float64(99*time.Millisecond)/16)
+}
+
+// Test for checking the counts and peaks of the octopus' statistics.
+func TestStats(t *testing.T) {
+	testOctopus, release := newBusyOctopus(t, queueCapacity)
+
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, testOctopus.HandleJob(func() {}, "queued"))
+	}
+
+	stats := testOctopus.Stats()
+	assert.Equal(t, uint64(4), stats.Submitted)
+	assert.Equal(t, 3, stats.QueueLength)
+	assert.Equal(t, 3, stats.PeakQueueLength)
+	assert.Equal(t, 1, stats.ActiveWorkers)
+	assert.Equal(t, 1, stats.PeakActiveWorkers)
+
+	close(release)
+	testOctopus.Wait()
+
+	stats = testOctopus.Stats()
+	assert.Equal(t, uint64(4), stats.Completed)
+	assert.Equal(t, 0, stats.QueueLength)
+	assert.Equal(t, 3, stats.PeakQueueLength)
+	assert.Equal(t, 0, stats.ActiveWorkers)
+	assert.Equal(t, uint64(3), stats.Jobs["queued"].Runs)
+	assert.Equal(t, uint64(1), stats.Jobs["blocker"].Runs)
+	assert.Greater(t, stats.Jobs["queued"].Wait.P50, time.Duration(0))
+}
+
+// Test for checking that cancelled and unscheduled jobs are counted.
+func TestStatsCancelled(t *testing.T) {
+	testOctopus, release := newBusyOctopus(t, queueCapacity)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	assert.NoError(t, testOctopus.HandleJobContext(ctx, func(ctx context.Context) error { return nil }, "cancelled"))
+	cancel()
+
+	scheduled, err := testOctopus.HandleJobAfter(time.Hour, func() {}, "unscheduled")
+	assert.NoError(t, err)
+	assert.True(t, scheduled.Cancel())
+
+	close(release)
+	testOctopus.Wait()
+
+	stats := testOctopus.Stats()
+	assert.Equal(t, uint64(3), stats.Submitted)
+	assert.Equal(t, uint64(1), stats.Completed)
+	assert.Equal(t, uint64(2), stats.Cancelled)
+	assert.Equal(t, uint64(0), stats.Discarded)
+}
+
+// Test for checking that queued and scheduled jobs discarded by ShutdownNow are counted.
+func TestStatsDiscarded(t *testing.T) {
+	testOctopus, release := newBusyOctopus(t, queueCapacity)
+	defer close(release)
+
+	assert.NoError(t, testOctopus.HandleJob(func() {}, "queued"))
+	_, err := testOctopus.HandleJobAfter(time.Hour, func() {}, "scheduled")
+	assert.NoError(t, err)
+
+	assert.Len(t, testOctopus.ShutdownNow(), 2)
+
+	stats := testOctopus.Stats()
+	assert.Equal(t, uint64(3), stats.Submitted)
+	assert.Equal(t, uint64(2), stats.Discarded)
+}
+
+// Test for checking the wait percentiles per job name, using a fake clock.
+func TestStatsWait(t *testing.T) {
+	clock := NewFakeClock(fakeStart)
+	testOctopus := NewOctopusWithOptions(1, queueCapacity, WithClock(clock))
+
+	started, release := make(chan struct{}), make(chan struct{})
+	assert.NoError(t, testOctopus.HandleJob(func() { close(started); <-release }, "blocker"))
+	assert.NoError(t, testOctopus.HandleJob(func() {}, "waiting"))
+	<-started
+
+	clock.Advance(time.Second)
+	close(release)
+	testOctopus.Wait()
+
+	stats := testOctopus.Stats()
+	assert.Equal(t, time.Duration(0), stats.Jobs["blocker"].Wait.P99)
+	assert.InDelta(t, float64(time.Second), float64(stats.Jobs["waiting"].Wait.P50), float64(time.Second)/16)
+}
+
+// Test for checking that the number of tracked job names is bounded.
+func TestStatsNames(t *testing.T) {
+	var stats poolStats
+	for i := 0; i < maxStatsNames+10; i++ {
+		stats.observeRun(fmt.Sprint("job ", i), time.Millisecond)
+	}
+
+	jobs := stats.jobStats()
+	assert.Len(t, jobs, maxStatsNames)
+	assert.NotContains(t, jobs, fmt.Sprint("job ", maxStatsNames))
+}
@@ -0,0 +1,160 @@
+// Copyright 2021 Aadhav Vignesh
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octopool
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Stress tests, meant to be run with the race detector: go test -race
+
+const (
+	stressSubmitters = 16  // number of goroutines submitting jobs
+	stressJobs       = 200 // number of jobs submitted by every goroutine
+)
+
+// Submits jobs from many goroutines using every submission API, and returns the number of accepted jobs.
+func stressSubmit(t *testing.T, testOctopus *Octopus, ran *int64) int64 {
+	var accepted int64
+	job := func() {
+		atomic.AddInt64(ran, 1)
+	}
+
+	var submitters sync.WaitGroup
+	for s := 0; s < stressSubmitters; s++ {
+		submitters.Add(1)
+		go func(s int) {
+			defer submitters.Done()
+
+			for i := 0; i < stressJobs; i++ {
+				var err error
+				switch (s + i) % 4 {
+				case 0:
+					err = testOctopus.HandleJob(job, "handle")
+				case 1:
+					_, err = testOctopus.Submit(func() (interface{}, error) {
+						job()
+						return nil, nil
+					}, "submit")
+				case 2:
+					if !testOctopus.TrySubmit(NewJob(job).WithName("try")) {
+						err = ErrQueueFull
+					}
+				case 3:
+					err = testOctopus.SubmitBlocking(context.Background(), NewJob(job).WithName("blocking"))
+				}
+
+				if err == nil {
+					atomic.AddInt64(&accepted, 1)
+					continue
+				}
+				if !errors.Is(err, ErrQueueFull) && !errors.Is(err, ErrInvalidPoolState) && !errors.Is(err, ErrQueueClosed) {
+					t.Errorf("Got unexpected error while submitting job: %v", err)
+				}
+			}
+		}(s)
+	}
+	submitters.Wait()
+
+	return atomic.LoadInt64(&accepted)
+}
+
+// Test for checking that every accepted job runs exactly once under concurrent submission, for every built-in queue.
+func TestStressConcurrentSubmit(t *testing.T) {
+	for name, testQueue := range builtinQueues(8) {
+		t.Run(name, func(t *testing.T) {
+			testOctopus := NewOctopusWithOptions(4, 8, WithQueue(testQueue), WithOverflowPolicy(OverflowBlock))
+
+			var ran int64
+			accepted := stressSubmit(t, testOctopus, &ran)
+			testOctopus.Wait()
+
+			// only TrySubmit may give up on a full pool
+			assert.GreaterOrEqual(t, accepted, int64(stressSubmitters*stressJobs*3/4), "blocking submissions should accept every job.")
+			assert.Equal(t, accepted, atomic.LoadInt64(&ran))
+			assert.Equal(t, 0, testOctopus.ActiveWorkers())
+			assert.Equal(t, 0, testOctopus.jobQueue.Len())
+		})
+	}
+}
+
+// Test for checking that Wait can be called while jobs are being submitted.
+func TestStressConcurrentWait(t *testing.T) {
+	testOctopus := NewOctopus(4, 8)
+
+	stop := make(chan struct{})
+	var waiters sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		waiters.Add(1)
+		go func() {
+			defer waiters.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					testOctopus.Wait()
+				}
+			}
+		}()
+	}
+
+	var ran int64
+	accepted := stressSubmit(t, testOctopus, &ran)
+	testOctopus.Wait()
+
+	close(stop)
+	waiters.Wait()
+
+	assert.Equal(t, accepted, atomic.LoadInt64(&ran))
+	assert.Equal(t, 4, testOctopus.AvailableWorkers())
+}
+
+// Test for checking that closing the pool while jobs are being submitted neither loses nor duplicates jobs.
+func TestStressConcurrentClose(t *testing.T) {
+	testOctopus := NewOctopusWithOptions(4, 8, WithOverflowPolicy(OverflowBlock))
+
+	var ran int64
+	var accepted int64
+	var submitting sync.WaitGroup
+	submitting.Add(1)
+	go func() {
+		defer submitting.Done()
+		accepted = stressSubmit(t, testOctopus, &ran)
+	}()
+
+	var closers sync.WaitGroup
+	for c := 0; c < 4; c++ {
+		closers.Add(1)
+		go func() {
+			defer closers.Done()
+			testOctopus.Close()
+		}()
+	}
+
+	closers.Wait()
+	submitting.Wait()
+	testOctopus.Wait()
+
+	assert.Equal(t, accepted, atomic.LoadInt64(&ran), "every accepted job should run once.")
+	assert.Equal(t, ErrInvalidPoolState, testOctopus.HandleJob(func() {}, "late"))
+	assert.Equal(t, 0, testOctopus.ActiveWorkers())
+}
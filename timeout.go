@@ -0,0 +1,144 @@
+// Copyright 2021 Aadhav Vignesh
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octopool
+
+import (
+	"context"
+	"errors"
+	"runtime/debug"
+	"time"
+)
+
+// Holds the outcome of a job executed in its own goroutine.
+type outcome struct {
+	result    interface{} // result of the job
+	err       error       // error of the job
+	recovered interface{} // value the job panicked with
+	panicked  bool        // reports whether the job panicked
+	stack     []byte      // stack trace of the panicking goroutine
+}
+
+// Carries a panic recovered in another goroutine to the worker, keeping the original stack trace.
+type recoveredPanic struct {
+	value interface{} // value the job panicked with
+	stack []byte      // stack trace of the panicking goroutine
+}
+
+// Returns the timeout for the job, zero if the job has none.
+func (octo *Octopus) timeoutFor(job Job) time.Duration {
+	if job.timeout > 0 {
+		return job.timeout
+	}
+	return octo.jobTimeout
+}
+
+// Returns a context which is cancelled with ErrJobTimeout as its cause once the timeout expires, ctx may be nil.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithTimeoutCause(ctx, timeout, ErrJobTimeout)
+}
+
+// Checks if the job's timeout expired.
+func timedOut(ctx context.Context) bool {
+	return ctx != nil && errors.Is(context.Cause(ctx), ErrJobTimeout)
+}
+
+// Executes the job with the timeout provided, zero means no timeout.
+// Jobs which outlive their timeout fail with ErrJobTimeout, and are abandoned if abandoning is enabled.
+func (octo *Octopus) execute(job Job, timeout time.Duration) (interface{}, error) {
+	if timeout <= 0 {
+		return job.execute()
+	}
+
+	if !octo.abandonOnTimeout {
+		result, err := job.execute()
+		if timedOut(job.ctx) {
+			return nil, octo.timeOut(job)
+		}
+		return result, err
+	}
+
+	// run the job in its own goroutine, so that the worker can leave it behind
+	done := make(chan outcome, 1)
+	go func() {
+		var out outcome
+		defer func() {
+			if r := recover(); r != nil {
+				out.recovered, out.panicked, out.stack = r, true, debug.Stack()
+			}
+			done <- out
+		}()
+		out.result, out.err = job.execute()
+	}()
+
+	select {
+	case out := <-done:
+		return octo.unwrapOutcome(job, out)
+	case <-job.ctx.Done():
+	}
+
+	if !timedOut(job.ctx) {
+		// the job was cancelled, cooperative jobs return shortly
+		out := <-done
+		return octo.unwrapOutcome(job, out)
+	}
+
+	octo.abandon(job, done)
+	return nil, octo.timeOut(job)
+}
+
+// Returns the job's result and error, raising the job's panic again in the calling goroutine.
+func (octo *Octopus) unwrapOutcome(job Job, out outcome) (interface{}, error) {
+	if out.panicked {
+		panic(&recoveredPanic{value: out.recovered, stack: out.stack})
+	}
+	if timedOut(job.ctx) {
+		return nil, octo.timeOut(job)
+	}
+	return out.result, out.err
+}
+
+// Logs a job which outlived its timeout, and returns the job's error.
+func (octo *Octopus) timeOut(job Job) error {
+	octo.logger.Warn("job timed out", "job", job.name, "timeout", octo.timeoutFor(job))
+	return ErrJobTimeout
+}
+
+// Leaves a job which outlived its timeout behind, and tracks its goroutine until the job returns.
+func (octo *Octopus) abandon(job Job, done <-chan outcome) {
+	octo.logger.Warn("abandoning timed out job, freeing its worker", "job", job.name)
+	octo.workerPool.abandonWorker()
+
+	go func() {
+		if out := <-done; out.panicked {
+			octo.logger.Error("abandoned job panicked", "job", job.name, "panic", out.recovered, "stack", string(out.stack))
+		}
+		octo.workerPool.doneAbandonedWorker()
+	}()
+}
+
+// TimedOutJobs returns the number of jobs which outlived their timeout, set by WithJobTimeout or TimeoutMiddleware.
+// A retried job is counted once, however many of its attempts timed out.
+func (octo *Octopus) TimedOutJobs() int64 {
+	return octo.timedOutJobs.Load()
+}
+
+// AbandonedJobs returns the number of abandoned jobs which are still running.
+// Abandoned jobs do not occupy a worker, and are not counted as active workers.
+func (octo *Octopus) AbandonedJobs() int {
+	return octo.workerPool.getAbandonedWorkersCount()
+}
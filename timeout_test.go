@@ -0,0 +1,140 @@
+// Copyright 2021 Aadhav Vignesh
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octopool
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test for checking that the context of a job is cancelled once its timeout expires.
+func TestJobTimeout(t *testing.T) {
+	testOctopus := NewOctopusWithOptions(1, queueCapacity, WithJobTimeout(20*time.Millisecond))
+
+	handle, err := testOctopus.SubmitContext(context.Background(), func(ctx context.Context) (interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}, "hanging")
+	assert.NoError(t, err)
+
+	_, err = handle.Wait()
+
+	assert.ErrorIs(t, err, ErrJobTimeout)
+	assert.Equal(t, int64(1), testOctopus.TimedOutJobs())
+}
+
+// Test for checking that jobs can override the octopus' job timeout.
+func TestJobTimeoutOverride(t *testing.T) {
+	testOctopus := NewOctopusWithOptions(1, queueCapacity, WithJobTimeout(10*time.Millisecond))
+
+	assert.True(t, testOctopus.TrySubmit(NewJob(func() {
+		time.Sleep(30 * time.Millisecond)
+	}).WithName("slow").WithTimeout(time.Second)))
+	assert.NoError(t, testOctopus.WaitErr())
+
+	// jobs which ignore their context are marked as timed out once they return
+	assert.True(t, testOctopus.TrySubmit(NewJob(func() {
+		time.Sleep(30 * time.Millisecond)
+	}).WithName("late")))
+
+	err := testOctopus.WaitErr()
+	assert.ErrorIs(t, err, ErrJobTimeout)
+	assert.Equal(t, []string{"late"}, failedJobNames(err))
+	assert.Equal(t, int64(1), testOctopus.TimedOutJobs())
+}
+
+// Test for checking that a retried job is counted once, however many of its attempts time out.
+func TestJobTimeoutRetry(t *testing.T) {
+	testOctopus := NewOctopusWithOptions(1, queueCapacity,
+		WithJobTimeout(5*time.Millisecond),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}),
+	)
+
+	var calls int32
+	handle, err := testOctopus.SubmitContext(context.Background(), func(ctx context.Context) (interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}, "hanging")
+	assert.NoError(t, err)
+
+	_, err = handle.Wait()
+
+	assert.ErrorIs(t, err, ErrJobTimeout)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+	assert.Equal(t, int64(1), testOctopus.TimedOutJobs())
+}
+
+// Test for checking that a job which outlives its timeout is abandoned, and frees its worker.
+func TestAbandonOnTimeout(t *testing.T) {
+	testOctopus := NewOctopusWithOptions(1, queueCapacity,
+		WithJobTimeout(20*time.Millisecond),
+		WithAbandonOnTimeout(),
+	)
+
+	release := make(chan struct{})
+	hanging, err := testOctopus.Submit(func() (interface{}, error) {
+		<-release
+		return "late", nil
+	}, "hanging")
+	assert.NoError(t, err)
+	next, err := testOctopus.Submit(func() (interface{}, error) {
+		return "next", nil
+	}, "next")
+	assert.NoError(t, err)
+
+	_, err = hanging.Wait()
+	assert.ErrorIs(t, err, ErrJobTimeout)
+
+	// the worker moved on while the hanging job is still running
+	result, err := next.Wait()
+	assert.NoError(t, err)
+	assert.Equal(t, "next", result)
+
+	testOctopus.Wait()
+	assert.Equal(t, 0, testOctopus.ActiveWorkers())
+	assert.Equal(t, 1, testOctopus.AbandonedJobs())
+
+	close(release)
+	assert.Eventually(t, func() bool {
+		return testOctopus.AbandonedJobs() == 0
+	}, time.Second, time.Millisecond)
+}
+
+// Test for checking that panics of jobs running in their own goroutine are still handled.
+func TestAbandonOnTimeoutPanic(t *testing.T) {
+	testOctopus := NewOctopusWithOptions(1, queueCapacity,
+		WithJobTimeout(time.Second),
+		WithAbandonOnTimeout(),
+	)
+
+	handle, err := testOctopus.Submit(func() (interface{}, error) {
+		panic("boom")
+	}, "panicking")
+	assert.NoError(t, err)
+
+	_, err = handle.Wait()
+
+	var panicErr *PanicError
+	assert.True(t, errors.As(err, &panicErr))
+	assert.Equal(t, "boom", panicErr.Value)
+	assert.Contains(t, string(panicErr.Stack), "TestAbandonOnTimeoutPanic")
+	assert.Equal(t, 0, testOctopus.AbandonedJobs())
+}
@@ -0,0 +1,68 @@
+// Copyright 2021 Aadhav Vignesh
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octopool
+
+import "context"
+
+// Tracer is an interface for tracing the lifecycle of jobs, the octotrace package provides an OpenTelemetry tracer.
+type Tracer interface {
+	// Submit is called when a job is handled, ctx is the context the job was handled with, nil for jobs which are not context-aware.
+	Submit(ctx context.Context, name string) JobTrace
+}
+
+// JobTrace is an interface for tracing a single job, its methods may be called from different goroutines.
+type JobTrace interface {
+	// Enqueued is called once the octopus accepted the job, which may happen after the job started.
+	Enqueued()
+	// Start is called before every execution of the job, the context returned is the one the job runs with.
+	// ctx is nil for jobs which are not context-aware, the context returned is ignored then.
+	Start(ctx context.Context) context.Context
+	// End is called after every execution of the job with its error, or with the reason the job never runs.
+	End(err error)
+}
+
+// Starts tracing the job if the octopus has a tracer.
+func (octo *Octopus) traceSubmit(job *Job) {
+	if octo.tracer != nil {
+		job.trace = octo.tracer.Submit(job.ctx, job.name)
+	}
+}
+
+// Traces the acceptance of the job.
+func (job Job) traceEnqueued() {
+	if job.trace != nil {
+		job.trace.Enqueued()
+	}
+}
+
+// Traces the start of an execution of the job, and returns the context the job runs with.
+func (job Job) traceStart(ctx context.Context) context.Context {
+	if job.trace == nil {
+		return ctx
+	}
+
+	traced := job.trace.Start(ctx)
+	if ctx == nil {
+		return nil
+	}
+	return traced
+}
+
+// Traces the end of an execution of the job, or the reason it never runs.
+func (job Job) traceEnd(err error) {
+	if job.trace != nil {
+		job.trace.End(err)
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright 2021 Aadhav Vignesh
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octopool
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingTracer is a struct for representing a tracer which records the events of every job.
+type recordingTracer struct {
+	events []string   // recorded events, prefixed with the job's name
+	mu     sync.Mutex // mutex for locking
+}
+
+// recordingTrace is a struct for representing the trace of a job recorded by a recordingTracer.
+type recordingTrace struct {
+	tracer *recordingTracer // records the events
+	name   string           // name of the job
+}
+
+// Records the event of the job.
+func (tracer *recordingTracer) record(name, event string) {
+	tracer.mu.Lock()
+	defer tracer.mu.Unlock()
+
+	tracer.events = append(tracer.events, name+" "+event)
+}
+
+// Returns the events of the job.
+func (tracer *recordingTracer) eventsOf(name string) []string {
+	tracer.mu.Lock()
+	defer tracer.mu.Unlock()
+
+	var events []string
+	for _, event := range tracer.events {
+		if len(event) > len(name) && event[:len(name)+1] == name+" " {
+			events = append(events, event[len(name)+1:])
+		}
+	}
+	return events
+}
+
+// Submit records the submission of the job.
+func (tracer *recordingTracer) Submit(ctx context.Context, name string) JobTrace {
+	tracer.record(name, "submit")
+	return &recordingTrace{tracer: tracer, name: name}
+}
+
+// Enqueued records the acceptance of the job.
+func (trace *recordingTrace) Enqueued() {
+	trace.tracer.record(trace.name, "enqueued")
+}
+
+// Start records the start of the job.
+func (trace *recordingTrace) Start(ctx context.Context) context.Context {
+	trace.tracer.record(trace.name, "start")
+	return ctx
+}
+
+// End records the end of the job.
+func (trace *recordingTrace) End(err error) {
+	trace.tracer.record(trace.name, fmt.Sprint("end ", err))
+}
+
+// Test for checking the events traced for queued, rejected and discarded jobs.
+func TestTracer(t *testing.T) {
+	tracer := &recordingTracer{}
+	testOctopus, release := newBusyOctopus(t, 1, WithTracer(tracer))
+
+	assert.NoError(t, testOctopus.HandleJob(func() {}, "queued"))
+	assert.Equal(t, ErrQueueFull, testOctopus.HandleJob(func() {}, "rejected"))
+	assert.False(t, testOctopus.TrySubmit(NewJob(func() {}).WithName("tried")))
+
+	close(release)
+	testOctopus.Wait()
+
+	assert.Equal(t, []string{"submit", "enqueued", "start", "end <nil>"}, tracer.eventsOf("queued"))
+	assert.Equal(t, []string{"submit", "end " + ErrQueueFull.Error()}, tracer.eventsOf("rejected"))
+	assert.Equal(t, []string{"submit", "end " + ErrQueueFull.Error()}, tracer.eventsOf("tried"))
+
+	testOctopus, release = newBusyOctopus(t, 1, WithTracer(tracer))
+	assert.NoError(t, testOctopus.HandleJob(func() {}, "discarded"))
+	testOctopus.ShutdownNow()
+	close(release)
+
+	assert.Equal(t, []string{"submit", "enqueued", "end " + ErrJobDiscarded.Error()}, tracer.eventsOf("discarded"))
+}
+
+// Test for checking that context-aware jobs run with the context returned by the trace.
+func TestTracerContext(t *testing.T) {
+	type key struct{}
+	testOctopus := NewOctopusWithOptions(1, queueCapacity, WithTracer(contextTracer{key: key{}}))
+
+	var value interface{}
+	assert.NoError(t, testOctopus.HandleJobContext(context.Background(), func(ctx context.Context) error {
+		value = ctx.Value(key{})
+		return nil
+	}))
+	assert.NoError(t, testOctopus.HandleJob(func() {}))
+	testOctopus.Wait()
+
+	assert.Equal(t, "traced", value)
+}
+
+// contextTracer is a struct for representing a tracer which adds a value to the context of jobs.
+type contextTracer struct {
+	key interface{} // key of the value
+}
+
+// Submit returns the trace of the job.
+func (tracer contextTracer) Submit(ctx context.Context, name string) JobTrace {
+	return tracer
+}
+
+// Enqueued does nothing.
+func (tracer contextTracer) Enqueued() {}
+
+// Start adds the value to the context, if the job is context-aware.
+func (tracer contextTracer) Start(ctx context.Context) context.Context {
+	if ctx == nil {
+		return nil
+	}
+	return context.WithValue(ctx, tracer.key, "traced")
+}
+
+// End does nothing.
+func (tracer contextTracer) End(err error) {}
@@ -0,0 +1,189 @@
+// Copyright 2021 Aadhav Vignesh
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octopool
+
+import (
+	"context"
+	"sync"
+)
+
+// TypedOctopus is a struct for representing an octopus which handles jobs transforming inputs into outputs.
+type TypedOctopus[In, Out any] struct {
+	octopus  *Octopus                                      // octopus which handles the jobs
+	function func(ctx context.Context, in In) (Out, error) // function applied to every input
+}
+
+// Future is a struct for representing the typed outcome of a job submitted to a TypedOctopus.
+type Future[Out any] struct {
+	handle *JobHandle // handle which receives the job's outcome
+}
+
+// NewTypedOctopus creates a typed octopus which applies fun to every submitted input, with the capacity specified.
+func NewTypedOctopus[In, Out any](fun func(ctx context.Context, in In) (Out, error), capacity int, queueCapacity ...int) *TypedOctopus[In, Out] {
+	return &TypedOctopus[In, Out]{
+		octopus:  NewOctopus(capacity, queueCapacity...),
+		function: fun,
+	}
+}
+
+// NewTypedOctopusWithOptions creates a typed octopus with the capacities specified, configured by the options provided.
+func NewTypedOctopusWithOptions[In, Out any](fun func(ctx context.Context, in In) (Out, error), capacity int, queueCapacity int, opts ...Option) *TypedOctopus[In, Out] {
+	return &TypedOctopus[In, Out]{
+		octopus:  NewOctopusWithOptions(capacity, queueCapacity, opts...),
+		function: fun,
+	}
+}
+
+// Octopus returns the octopus which handles the typed jobs.
+func (typed *TypedOctopus[In, Out]) Octopus() *Octopus {
+	return typed.octopus
+}
+
+// Submit handles a job for the input and returns a future for collecting its output.
+func (typed *TypedOctopus[In, Out]) Submit(in In) *Future[Out] {
+	return typed.SubmitContext(context.Background(), in)
+}
+
+// SubmitContext handles a context-aware job for the input and returns a future for collecting its output.
+// Errors raised while handling the job are delivered through the future.
+func (typed *TypedOctopus[In, Out]) SubmitContext(ctx context.Context, in In) *Future[Out] {
+	future, err := typed.submit(ctx, in, typed.function, false)
+	if err != nil {
+		return failedFuture[Out](err)
+	}
+	return future
+}
+
+// Submits a job applying fun to the input, blocking while the job queue is full if block is true.
+func (typed *TypedOctopus[In, Out]) submit(ctx context.Context, in In, fun func(ctx context.Context, in In) (Out, error), block bool) (*Future[Out], error) {
+	if fun == nil {
+		return nil, ErrNilFunction
+	}
+
+	apply := func(ctx context.Context) (interface{}, error) {
+		return fun(ctx, in)
+	}
+	if !block {
+		handle, err := typed.octopus.SubmitContext(ctx, apply)
+		if err != nil {
+			return nil, err
+		}
+		return &Future[Out]{handle: handle}, nil
+	}
+
+	job := Job{ctxFunction: apply, ctx: ctx}
+	job.handle = newJobHandle(job.name)
+	if err := typed.octopus.SubmitBlocking(ctx, job); err != nil {
+		return nil, err
+	}
+
+	return &Future[Out]{handle: job.handle}, nil
+}
+
+// Map handles a job for every input and returns the outputs in the order of the inputs.
+// The remaining jobs are cancelled on the first error, which is returned along with the outputs collected so far.
+// Map blocks while the job queue is full, so any number of inputs can be mapped, until ctx is done.
+func (typed *TypedOctopus[In, Out]) Map(ctx context.Context, ins []In) ([]Out, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// record the first error and cancel the remaining jobs
+	var firstErr error
+	var failOnce sync.Once
+	fail := func(err error) {
+		failOnce.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	fun := typed.function
+	if fun != nil {
+		fun = func(ctx context.Context, in In) (Out, error) {
+			out, err := typed.function(ctx, in)
+			if err != nil {
+				fail(err)
+			}
+			return out, err
+		}
+	}
+
+	futures := make([]*Future[Out], len(ins))
+	for i, in := range ins {
+		future, err := typed.submit(ctx, in, fun, true)
+		if err != nil {
+			fail(err)
+			future = failedFuture[Out](err)
+		}
+		futures[i] = future
+	}
+
+	// wait for every job, so that no job is running once Map returns
+	outs := make([]Out, len(ins))
+	var skipped error
+	for i, future := range futures {
+		out, err := future.Wait()
+		if err != nil {
+			skipped = err
+			continue
+		}
+		outs[i] = out
+	}
+
+	if firstErr != nil {
+		return outs, firstErr
+	}
+	return outs, skipped
+}
+
+// Wait waits on workers to finish the jobs.
+func (typed *TypedOctopus[In, Out]) Wait() {
+	typed.octopus.Wait()
+}
+
+// Close closes the worker pool.
+func (typed *TypedOctopus[In, Out]) Close() {
+	typed.octopus.Close()
+}
+
+// Returns a future which has already failed with err.
+func failedFuture[Out any](err error) *Future[Out] {
+	handle := newJobHandle("")
+	handle.finish(nil, err)
+	return &Future[Out]{handle: handle}
+}
+
+// Done returns a channel which is closed once the job has finished.
+func (future *Future[Out]) Done() <-chan struct{} {
+	return future.handle.Done()
+}
+
+// Wait blocks until the job has finished and returns its output and error.
+func (future *Future[Out]) Wait() (Out, error) {
+	result, err := future.handle.Wait()
+	out, _ := result.(Out)
+	return out, err
+}
+
+// Result blocks until the job has finished and returns its output.
+func (future *Future[Out]) Result() Out {
+	out, _ := future.Wait()
+	return out
+}
+
+// Err blocks until the job has finished and returns its error.
+func (future *Future[Out]) Err() error {
+	return future.handle.Err()
+}
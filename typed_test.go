@@ -0,0 +1,124 @@
+// Copyright 2021 Aadhav Vignesh
+
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+
+// 	http://www.apache.org/licenses/LICENSE-2.0
+
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package octopool
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test for checking the output of a job submitted to a typed octopus.
+func TestTypedOctopusSubmit(t *testing.T) {
+	testOctopus := NewTypedOctopus(func(ctx context.Context, in int) (string, error) {
+		return strconv.Itoa(in * 2), nil
+	}, 2)
+
+	out, err := testOctopus.Submit(21).Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "42", out)
+}
+
+// Test for checking that Map preserves the order of the inputs.
+func TestTypedOctopusMap(t *testing.T) {
+	testOctopus := NewTypedOctopus(func(ctx context.Context, in int) (int, error) {
+		// finish later inputs first
+		time.Sleep(time.Duration(10-in) * time.Millisecond)
+		return in * in, nil
+	}, 3)
+
+	outs, err := testOctopus.Map(context.Background(), []int{1, 2, 3, 4, 5, 6, 7, 8, 9})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 4, 9, 16, 25, 36, 49, 64, 81}, outs)
+}
+
+// Test for checking that Map waits for queue slots when there are more inputs than workers and queue slots.
+func TestTypedOctopusMapFullQueue(t *testing.T) {
+	testOctopus := NewTypedOctopusWithOptions(func(ctx context.Context, in int) (int, error) {
+		time.Sleep(time.Millisecond)
+		return in * 2, nil
+	}, 2, 2, WithOverflowPolicy(OverflowReject))
+
+	ins := make([]int, 20)
+	want := make([]int, 20)
+	for i := range ins {
+		ins[i] = i
+		want[i] = i * 2
+	}
+
+	outs, err := testOctopus.Map(context.Background(), ins)
+
+	assert.NoError(t, err)
+	assert.Equal(t, want, outs)
+}
+
+// Test for checking that Map stops waiting for queue slots once its context is done.
+func TestTypedOctopusMapCancel(t *testing.T) {
+	release := make(chan struct{})
+	testOctopus := NewTypedOctopus(func(ctx context.Context, in int) (int, error) {
+		<-release
+		return in, nil
+	}, 1, 1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	time.AfterFunc(50*time.Millisecond, func() { close(release) })
+
+	_, err := testOctopus.Map(ctx, []int{1, 2, 3, 4})
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+// Test for checking that Map returns the first error and skips the remaining jobs.
+func TestTypedOctopusMapError(t *testing.T) {
+	errOdd := errors.New("odd input")
+	var calls int32
+
+	testOctopus := NewTypedOctopus(func(ctx context.Context, in int) (int, error) {
+		atomic.AddInt32(&calls, 1)
+		if in == 1 {
+			return 0, errOdd
+		}
+		return in, nil
+	}, 1)
+
+	// the first job fails while the remaining jobs wait in the queue
+	_, err := testOctopus.Map(context.Background(), []int{1, 2, 4, 6, 8})
+
+	assert.Equal(t, errOdd, err)
+	assert.Less(t, atomic.LoadInt32(&calls), int32(5), "remaining jobs should be cancelled.")
+}
+
+// Test for checking that errors raised while handling a job are delivered through the future.
+func TestTypedOctopusSubmitInvalid(t *testing.T) {
+	testOctopus := NewTypedOctopus[int, int](nil, 1)
+
+	_, err := testOctopus.Submit(1).Wait()
+	assert.Equal(t, ErrNilFunction, err)
+
+	testOctopus = NewTypedOctopus(func(ctx context.Context, in int) (int, error) { return in, nil }, 1)
+	testOctopus.Close()
+
+	future := testOctopus.Submit(1)
+	<-future.Done()
+	assert.Equal(t, ErrInvalidPoolState, future.Err())
+}
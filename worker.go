@@ -14,35 +14,53 @@
 
 package octopool
 
-import "log"
+import "time"
 
+// worker is a struct for representing a long-lived goroutine which executes jobs.
+// Once a job is finished, the worker takes the next job from the job queue, and
+// parks itself when the queue is empty, until the pool assigns it a new job.
 type worker struct {
-	jobs chan func() // channel for receiving jobs
-	pool *pool       // pool reference
+	jobs     chan Job  // channel for receiving jobs while parked
+	pool     *pool     // pool reference
+	parkedAt time.Time // time at which the worker was parked
 }
 
-// Executes the job provided to the worker.
-func (w *worker) run() {
-	w.pool.wg.Add(1)
-	go func() {
-		defer w.pool.wg.Done()
-		defer func() {
-			// silently recover from error, do not panic
-			if r := recover(); r != nil {
-				// print the error to the console
-				log.Printf("Recovered error: %v\n", r)
-				// return the worker back due to abrupt failure
-				w.pool.newWorkerAvailable(w)
+// Executes jobs until the pool stops the worker, starting with the job provided.
+func (w *worker) run(job Job) {
+	octopus := w.pool.octopus
+
+	for {
+		// execute job, failed jobs waiting for a retry are still pending
+		finished := octopus.runJob(job)
+
+		// keep the reservation for the next queued job, if there is one and the pool was not shrunk
+		if w.pool.canKeepWorker() {
+			if next, ok := octopus.takeNext(); ok {
+				if finished {
+					w.pool.donePendingJob()
+				}
+				job = next
+				continue
 			}
-		}()
+		}
 
-		// receive job
-		job := <-w.jobs
+		// return worker back once the job queue is empty, before the finished job stops being pending
+		parked := w.pool.parkWorker(w)
+		if finished {
+			w.pool.donePendingJob()
+		}
 
-		// execute job
-		job()
+		// a job may have been queued while the worker was still reserved
+		octopus.processNext()
+		if !parked {
+			return
+		}
 
-		// return worker back once job is completed
-		w.pool.newWorkerAvailable(w)
-	}()
+		// wait for a new job, the channel is closed when the worker should stop
+		var ok bool
+		job, ok = <-w.jobs
+		if !ok {
+			return
+		}
+	}
 }
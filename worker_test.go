@@ -41,3 +41,59 @@ func TestWorker(t *testing.T) {
 	// if worker returns error, then it will be available
 	assert.Equal(t, 1, testOctopus.AvailableWorkers())
 }
+
+func TestWorkerReuse(t *testing.T) {
+	testOctopus := NewOctopus(2)
+
+	for i := 0; i < 100; i++ {
+		err := testOctopus.HandleJob(func() {}, "short")
+		if err != nil {
+			t.Errorf("Got error while handling job: %v", err)
+		}
+		testOctopus.Wait()
+	}
+
+	testOctopus.workerPool.mu.Lock()
+	defer testOctopus.workerPool.mu.Unlock()
+
+	// workers are parked and reused instead of being started for every job
+	assert.LessOrEqual(t, testOctopus.workerPool.spawnedWorkers, 2)
+	assert.Equal(t, testOctopus.workerPool.spawnedWorkers, len(testOctopus.workerPool.idleWorkers))
+}
+
+func TestWorkerPanicReuse(t *testing.T) {
+	testOctopus := NewOctopus(1)
+
+	for i := 0; i < 10; i++ {
+		err := testOctopus.HandleJob(func() { panic("octopus down") }, "panicking")
+		if err != nil {
+			t.Errorf("Got error while handling job: %v", err)
+		}
+		testOctopus.Wait()
+	}
+
+	testOctopus.workerPool.mu.Lock()
+	defer testOctopus.workerPool.mu.Unlock()
+
+	// the worker survives panicking jobs
+	assert.Equal(t, 1, testOctopus.workerPool.spawnedWorkers)
+}
+
+func TestWorkerStopOnClose(t *testing.T) {
+	testOctopus := NewOctopus(4)
+
+	for i := 0; i < 4; i++ {
+		err := testOctopus.HandleJob(func() { time.Sleep(10 * time.Millisecond) }, "sleepy")
+		if err != nil {
+			t.Errorf("Got error while handling job: %v", err)
+		}
+	}
+	testOctopus.Wait()
+	testOctopus.Close()
+
+	testOctopus.workerPool.mu.Lock()
+	defer testOctopus.workerPool.mu.Unlock()
+
+	assert.Equal(t, 0, testOctopus.workerPool.spawnedWorkers)
+	assert.Empty(t, testOctopus.workerPool.idleWorkers)
+}